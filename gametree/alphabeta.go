@@ -1,33 +1,52 @@
 package gametree
 
 import (
+	"context"
 	"math"
 
 	"github.com/JonasBernard/min-cost-max-flow/graph"
 	"github.com/JonasBernard/min-cost-max-flow/graphlike"
+	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
-type GameState[T any] interface {
-	graphlike.RootedTreelike[GameState[T]]
+/*
+A game given by its game tree, whose root is the current position. A move is an edge from the root
+to one of its children, and ApplyMove returns the game after that move, rooted at the child.
+*/
+type GameState[T graph.Node] interface {
+	graphlike.RootedTreelike[T]
 	Evaluate() float64
 	IsTerminal() bool
-	ApplyMove(*graph.WeightedDirectedEdge[GameState[T]]) graph.WeigthedDirectedGraph[GameState[T]]
+	ApplyMove(*graph.WeightedDirectedEdge[T, float64]) GameState[T]
 }
 
 // AlphaBetaSearch performs the alpha-beta pruning algorithm on a game tree.
 // It returns the best score and the corresponding move.
-func AlphaBetaSearch[V graph.Node, T GameState[V]](g graph.WeigthedDirectedGraph[V], depth int, alpha, beta float64, maximizingPlayer bool) (float64, *graph.WeightedDirectedEdge[V]) {
+func AlphaBetaSearch[T graph.Node](g GameState[T], depth int, alpha, beta float64, maximizingPlayer bool) (float64, *graph.WeightedDirectedEdge[T, float64]) {
+	eval, bestMove, _ := AlphaBetaSearchContext(context.Background(), g, depth, alpha, beta, maximizingPlayer)
+	return eval, bestMove
+}
+
+// AlphaBetaSearchContext is AlphaBetaSearch, but gives up once ctx is done.
+// It then returns the best score and move among the moves that were searched completely,
+// together with an error that matches util.ErrInterrupted.
+func AlphaBetaSearchContext[T graph.Node](ctx context.Context, g GameState[T], depth int, alpha, beta float64, maximizingPlayer bool) (float64, *graph.WeightedDirectedEdge[T, float64], error) {
 	if depth == 0 || g.IsTerminal() {
-		return g.Evaluate(), nil
+		return g.Evaluate(), nil, nil
 	}
 
-	var bestMove *graph.WeightedDirectedEdge[V]
+	var bestMove *graph.WeightedDirectedEdge[T, float64]
 
 	if maximizingPlayer {
 		maxEval := math.Inf(-1)
-		for _, edge := range g.Edges {
-			childGraph := g.ApplyMove(edge)
-			eval, _ := AlphaBetaSearch(childGraph, depth-1, alpha, beta, false)
+		if err := util.Interrupted(ctx); err != nil {
+			return maxEval, bestMove, err
+		}
+		for _, edge := range moves(g) {
+			eval, _, err := AlphaBetaSearchContext(ctx, g.ApplyMove(edge), depth-1, alpha, beta, false)
+			if err != nil {
+				return maxEval, bestMove, err
+			}
 			if eval > maxEval {
 				maxEval = eval
 				bestMove = edge
@@ -37,12 +56,17 @@ func AlphaBetaSearch[V graph.Node, T GameState[V]](g graph.WeigthedDirectedGraph
 				break
 			}
 		}
-		return maxEval, bestMove
+		return maxEval, bestMove, nil
 	} else {
 		minEval := math.Inf(1)
-		for _, edge := range g.Edges {
-			childGraph := g.ApplyMove(edge)
-			eval, _ := AlphaBetaSearch(childGraph, depth-1, alpha, beta, true)
+		if err := util.Interrupted(ctx); err != nil {
+			return minEval, bestMove, err
+		}
+		for _, edge := range moves(g) {
+			eval, _, err := AlphaBetaSearchContext(ctx, g.ApplyMove(edge), depth-1, alpha, beta, true)
+			if err != nil {
+				return minEval, bestMove, err
+			}
 			if eval < minEval {
 				minEval = eval
 				bestMove = edge
@@ -52,6 +76,17 @@ func AlphaBetaSearch[V graph.Node, T GameState[V]](g graph.WeigthedDirectedGraph
 				break
 			}
 		}
-		return minEval, bestMove
+		return minEval, bestMove, nil
+	}
+}
+
+// The edges from the root of the game tree to its children.
+func moves[T graph.Node](g GameState[T]) []*graph.WeightedDirectedEdge[T, float64] {
+	root := g.Root()
+	children := g.Children(root)
+	edges := make([]*graph.WeightedDirectedEdge[T, float64], len(children))
+	for i := range children {
+		edges[i] = graph.E[T, float64](graph.V(&root), graph.V(&children[i]), 0, 0)
 	}
+	return edges
 }
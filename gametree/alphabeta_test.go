@@ -0,0 +1,126 @@
+package gametree_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/gametree"
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+type Position string
+
+func (p Position) String() string {
+	return string(p)
+}
+
+// An explicit game tree rooted at root. evaluated is called for every position that gets evaluated.
+type Tree struct {
+	root      Position
+	children  map[Position][]Position
+	values    map[Position]float64
+	evaluated func(Position)
+}
+
+func (t Tree) NeightboursOf(p Position) []Position {
+	return t.children[p]
+}
+
+func (t Tree) Root() Position {
+	return t.root
+}
+
+func (t Tree) Parent(p Position) Position {
+	for parent, children := range t.children {
+		for _, child := range children {
+			if child == p {
+				return parent
+			}
+		}
+	}
+	return ""
+}
+
+func (t Tree) Children(p Position) []Position {
+	return t.children[p]
+}
+
+func (t Tree) IsLeaf(p Position) bool {
+	return len(t.children[p]) == 0
+}
+
+func (t Tree) Evaluate() float64 {
+	if t.evaluated != nil {
+		t.evaluated(t.root)
+	}
+	return t.values[t.root]
+}
+
+func (t Tree) IsTerminal() bool {
+	return t.IsLeaf(t.root)
+}
+
+func (t Tree) ApplyMove(move *graph.WeightedDirectedEdge[Position, float64]) gametree.GameState[Position] {
+	t.root = *move.VertexTo.Node
+	return t
+}
+
+/*
+The maximizer moves at r, the minimizer at a and b, the maximizer again at a1, a2, b1 and b2:
+a is worth min(max(3, 5), max(2, 9)) = 5 and b is worth min(max(7, 8), max(6, 10)) = 8.
+*/
+func exampleTree() Tree {
+	return Tree{
+		root: "r",
+		children: map[Position][]Position{
+			"r":  {"a", "b"},
+			"a":  {"a1", "a2"},
+			"b":  {"b1", "b2"},
+			"a1": {"a11", "a12"},
+			"a2": {"a21", "a22"},
+			"b1": {"b11", "b12"},
+			"b2": {"b21", "b22"},
+		},
+		values: map[Position]float64{
+			"a11": 3, "a12": 5, "a21": 2, "a22": 9,
+			"b11": 7, "b12": 8, "b21": 6, "b22": 10,
+		},
+	}
+}
+
+func TestAlphaBetaSearch(test *testing.T) {
+	eval, move := gametree.AlphaBetaSearch[Position](exampleTree(), 3, math.Inf(-1), math.Inf(1), true)
+	if eval != 8 {
+		test.Errorf("Expected score 8, got %v", eval)
+	}
+	if move == nil || *move.VertexTo.Node != "b" {
+		test.Errorf("Expected the move to b, got %v", move)
+	}
+}
+
+func TestAlphaBetaSearchContext(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the search is cancelled once the subtree of a has been searched completely
+	tree := exampleTree()
+	tree.evaluated = func(p Position) {
+		if p == "a22" {
+			cancel()
+		}
+	}
+
+	eval, move, err := gametree.AlphaBetaSearchContext[Position](ctx, tree, 3, math.Inf(-1), math.Inf(1), true)
+	if !errors.Is(err, util.ErrInterrupted) {
+		test.Errorf("Expected an error that matches util.ErrInterrupted, got %v", err)
+	}
+	if eval != 5 {
+		test.Errorf("Expected the score 5 of the incumbent, got %v", eval)
+	}
+	if move == nil || *move.VertexTo.Node != "a" {
+		test.Errorf("Expected the incumbent move to a, got %v", move)
+	}
+}
@@ -2,11 +2,13 @@ module github.com/JonasBernard/min-cost-max-flow
 
 go 1.22
 
-require github.com/stretchr/testify v1.11.1
+require (
+	github.com/jedib0t/go-pretty/v6 v6.7.5
+	github.com/stretchr/testify v1.11.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/jedib0t/go-pretty/v6 v6.7.5 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
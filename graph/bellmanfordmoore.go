@@ -1,23 +1,43 @@
 package graph
 
-import "math"
+import (
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
 
 /*
 Constructs a map that maps every vertex to the shortest distance from s to it using Bellman-Ford-Moore.
+Vertices that are not reachable from s have distance util.Infinity[N]().
 */
-func (g WeigthedDirectedGraph[T]) BellmanFordMoore(s Vertex[T]) (distances map[Vertex[T]]float64) {
-	distances = make(map[Vertex[T]]float64)
+func (g WeigthedDirectedGraph[T, N]) BellmanFordMoore(s Vertex[T]) (distances map[Vertex[T]]N) {
+	return g.BellmanFordMooreWithin(s, util.DefaultTolerance)
+}
+
+/*
+Same as BellmanFordMoore, but a distance is only updated if it improves by more than the given tolerance.
+This keeps cycles whose weights only cancel up to rounding errors from counting as negative cycles.
+For integer weights the tolerance is ignored and all comparisons are exact.
+*/
+func (g WeigthedDirectedGraph[T, N]) BellmanFordMooreWithin(s Vertex[T], tol util.Tolerance) (distances map[Vertex[T]]N) {
+	infinity := util.Infinity[N]()
+	distances = make(map[Vertex[T]]N)
 	for _, v := range g.Vertices {
 		if v.Node == s.Node {
 			distances[v] = 0
 		} else {
-			distances[v] = math.Inf(+1)
+			distances[v] = infinity
 		}
 	}
 	n := len(g.Vertices)
 	for k := 0; k < n; k++ {
 		for _, edge := range g.Edges {
-			distances[edge.VertexTo] = math.Min(distances[edge.VertexTo], distances[edge.VertexFrom]+edge.Weight)
+			// relaxing from unreached vertices would overflow for integer weights
+			if distances[edge.VertexFrom] == infinity {
+				continue
+			}
+			candidate := distances[edge.VertexFrom] + edge.Weight
+			if util.CompareWithin(tol, candidate, distances[edge.VertexTo]) < 0 {
+				distances[edge.VertexTo] = candidate
+			}
 		}
 	}
 	return
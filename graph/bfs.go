@@ -8,7 +8,7 @@ If the parameter "find" is non-nil, the algorithm will stop and return early upo
 It returns the resulting partent structure and the depths of the vertices.
 They both depend on the order in which the edges of the graph are given.
 */
-func (p WeigthedDirectedGraph[T]) BFS(root Vertex[T], find *Vertex[T]) (parents map[Vertex[T]]Vertex[T], depths map[Vertex[T]]int) {
+func (p WeigthedDirectedGraph[T, N]) BFS(root Vertex[T], find *Vertex[T]) (parents map[Vertex[T]]Vertex[T], depths map[Vertex[T]]int) {
 	p = p.resetVisited()
 	parents = make(map[Vertex[T]]Vertex[T])
 	depths = make(map[Vertex[T]]int)
@@ -50,10 +50,10 @@ Invokes BFS on the graph starting at node root and stops as soon as it reaches
 the to node. Then returns the shortest path from root to "to" in terms of hop distance.
 Returns an error if there is no shortest path.
 */
-func (p WeigthedDirectedGraph[T]) BFSShortestHopPathTo(root Vertex[T], to Vertex[T]) (*WeigthedDirectedGraph[T], error) {
+func (p WeigthedDirectedGraph[T, N]) BFSShortestHopPathTo(root Vertex[T], to Vertex[T]) (*WeigthedDirectedGraph[T, N], error) {
 	parents, _ := p.BFS(root, &to)
 
-	path := WeigthedDirectedGraph[T]{[]Vertex[T]{to}, []*WeightedDirectedEdge[T]{}}
+	path := WeigthedDirectedGraph[T, N]{[]Vertex[T]{to}, []*WeightedDirectedEdge[T, N]{}}
 	head := to
 	for {
 		if head.Node == root.Node {
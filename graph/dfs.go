@@ -5,7 +5,7 @@ Performs an iterative depth-first-search on the graph starting at the given root
 It returns the resulting partent structure and the depths of the vertices.
 They both depend on the order in which the edges of the graph are given.
 */
-func (p WeigthedDirectedGraph[T]) DFS(root Vertex[T]) (parents map[Vertex[T]]Vertex[T], depths map[Vertex[T]]int) {
+func (p WeigthedDirectedGraph[T, N]) DFS(root Vertex[T]) (parents map[Vertex[T]]Vertex[T], depths map[Vertex[T]]int) {
 	p = p.resetVisited()
 	parents = make(map[Vertex[T]]Vertex[T])
 	depths = make(map[Vertex[T]]int)
@@ -11,8 +11,8 @@ func V[T Node](node *T) Vertex[T] {
 	return Vertex[T]{Node: node}
 }
 
-func E[T Node](from, to Vertex[T], weight float64, capacity float64) *WeightedDirectedEdge[T] {
-	return &WeightedDirectedEdge[T]{VertexFrom: from, VertexTo: to, Weight: weight, Capacity: capacity}
+func E[T Node, N util.Number](from, to Vertex[T], weight N, capacity N) *WeightedDirectedEdge[T, N] {
+	return &WeightedDirectedEdge[T, N]{VertexFrom: from, VertexTo: to, Weight: weight, Capacity: capacity}
 }
 
 type Node interface {
@@ -46,25 +46,30 @@ type Vertex[T Node] struct {
 	Visited bool
 }
 
-type WeightedDirectedEdge[T Node] struct {
+/*
+An edge with weight and capacity of numeric type N, which is either int64 or float64.
+With int64 all algorithms in this module run in exact integer arithmetic, so for example
+min cost flows on integral instances are integral without any rounding.
+*/
+type WeightedDirectedEdge[T Node, N util.Number] struct {
 	VertexFrom   Vertex[T]
 	VertexTo     Vertex[T]
-	Weight       float64
-	Capacity     float64
+	Weight       N
+	Capacity     N
 	IsReverseArc bool
-	OriginalEdge *WeightedDirectedEdge[T]
+	OriginalEdge *WeightedDirectedEdge[T, N]
 }
 
-type WeigthedDirectedGraph[T Node] struct {
+type WeigthedDirectedGraph[T Node, N util.Number] struct {
 	Vertices []Vertex[T]
-	Edges    []*WeightedDirectedEdge[T]
+	Edges    []*WeightedDirectedEdge[T, N]
 }
 
 /*
-Checks if this vertex is part of a given graph, where technically we check wheather the vertieces
+Checks if the vertex v is part of a given graph, where technically we check wheather the vertieces
 node that acts like an ID is present in some vertex of the given graph.
 */
-func (v Vertex[T]) IsInGraph(g WeigthedDirectedGraph[T]) bool {
+func IsInGraph[T Node, N util.Number](v Vertex[T], g WeigthedDirectedGraph[T, N]) bool {
 	for _, vert := range g.Vertices {
 		if v.Node == vert.Node {
 			return true
@@ -80,8 +85,8 @@ The function assumes such an edge exists in the graph the function is executed o
 This is useful to get the information that is associated with some edge such as the weight without having access to an
 instance of the actual edge but only its endpoints.
 */
-func (g WeigthedDirectedGraph[T]) getEdge(from Vertex[T], to Vertex[T]) *WeightedDirectedEdge[T] {
-	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T]) bool {
+func (g WeigthedDirectedGraph[T, N]) getEdge(from Vertex[T], to Vertex[T]) *WeightedDirectedEdge[T, N] {
+	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T, N]) bool {
 		return e.VertexFrom.Node == from.Node && e.VertexTo.Node == to.Node
 	})[0]
 }
@@ -94,7 +99,7 @@ func (v *Vertex[T]) Visit() {
 /*
 Returns an identical graph to the one given, but all vertices are marked unvisited
 */
-func (g WeigthedDirectedGraph[T]) resetVisited() WeigthedDirectedGraph[T] {
+func (g WeigthedDirectedGraph[T, N]) resetVisited() WeigthedDirectedGraph[T, N] {
 	for _, v := range g.Vertices {
 		v.Visited = false
 	}
@@ -104,27 +109,27 @@ func (g WeigthedDirectedGraph[T]) resetVisited() WeigthedDirectedGraph[T] {
 /*
 Returns a slice of all vertices in the graph that are currently marked visited
 */
-func (g WeigthedDirectedGraph[T]) GetVisitedVertices() []Vertex[T] {
+func (g WeigthedDirectedGraph[T, N]) GetVisitedVertices() []Vertex[T] {
 	return util.FilterSlice(g.Vertices, func(v Vertex[T]) bool { return v.Visited })
 }
 
 /*
 Returns a slice of all outgoing edges of v in the graph g.
 */
-func (g WeigthedDirectedGraph[T]) OutgoingEdgesOf(v Vertex[T]) []*WeightedDirectedEdge[T] {
-	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T]) bool { return e.VertexFrom.Node == v.Node })
+func (g WeigthedDirectedGraph[T, N]) OutgoingEdgesOf(v Vertex[T]) []*WeightedDirectedEdge[T, N] {
+	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T, N]) bool { return e.VertexFrom.Node == v.Node })
 }
 
 /*
 Returns a slice containing all (outgoing) neighbours (vertices) of v in the graph g.
 */
-func (g WeigthedDirectedGraph[T]) NeightboursOf(v Vertex[T]) []Vertex[T] {
-	return util.MapSlice(g.OutgoingEdgesOf(v), func(e **WeightedDirectedEdge[T]) Vertex[T] { return (*e).VertexTo })
+func (g WeigthedDirectedGraph[T, N]) NeightboursOf(v Vertex[T]) []Vertex[T] {
+	return util.MapSlice(g.OutgoingEdgesOf(v), func(e **WeightedDirectedEdge[T, N]) Vertex[T] { return (*e).VertexTo })
 }
 
 /*
 Returns a slice of all incoming edges of v in the graph g.
 */
-func (g WeigthedDirectedGraph[T]) IncomingEdgesOf(v Vertex[T]) []*WeightedDirectedEdge[T] {
-	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T]) bool { return e.VertexTo.Node == v.Node })
+func (g WeigthedDirectedGraph[T, N]) IncomingEdgesOf(v Vertex[T]) []*WeightedDirectedEdge[T, N] {
+	return util.FilterSlice(g.Edges, func(e *WeightedDirectedEdge[T, N]) bool { return e.VertexTo.Node == v.Node })
 }
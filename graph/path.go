@@ -1,14 +1,18 @@
 package graph
 
-import "github.com/JonasBernard/min-cost-max-flow/util"
+import (
+	"strings"
 
-type Path[T Node] WeigthedDirectedGraph[T]
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+type Path[T Node, N util.Number] WeigthedDirectedGraph[T, N]
 
 /*
 Checks if the graph is just a path from the given start vertex
 aka a tree with root start and only one leaf of depth |V|
 */
-func (g WeigthedDirectedGraph[T]) IsPathFrom(start Vertex[T]) bool {
+func (g WeigthedDirectedGraph[T, N]) IsPathFrom(start Vertex[T]) bool {
 	parents, depths := g.BFS(start, nil)
 	for _, v := range g.Vertices {
 		_, ok := parents[v]
@@ -19,3 +23,18 @@ func (g WeigthedDirectedGraph[T]) IsPathFrom(start Vertex[T]) bool {
 	depthsAsFloats := util.MapMapValues[Vertex[T], int, float64](depths, func(d int) float64 { return float64(d) })
 	return util.MaxMapValue(depthsAsFloats)+1 == float64(len(g.Vertices))
 }
+
+// The nodes along the edges of the path, like A -> C -> F, where a cycle ends with its first node again.
+func (p Path[T, N]) String() string {
+	if len(p.Edges) == 0 {
+		if len(p.Vertices) == 0 {
+			return ""
+		}
+		return (*p.Vertices[0].Node).String()
+	}
+	nodes := []string{(*p.Edges[0].VertexFrom.Node).String()}
+	for _, e := range p.Edges {
+		nodes = append(nodes, (*e.VertexTo.Node).String())
+	}
+	return strings.Join(nodes, " -> ")
+}
@@ -11,16 +11,24 @@ import (
 Reconstructs a shortest path from the distances that Bellman-Ford-Moore returned.
 Returns an error if no path exists or a negative cycle ocurrs.
 */
-func (g WeigthedDirectedGraph[T]) ShortestPathFromDistances(distances map[Vertex[T]]float64, s Vertex[T], t Vertex[T]) (*WeigthedDirectedGraph[T], error) {
-	path := WeigthedDirectedGraph[T]{[]Vertex[T]{t}, []*WeightedDirectedEdge[T]{}}
+func (g WeigthedDirectedGraph[T, N]) ShortestPathFromDistances(distances map[Vertex[T]]N, s Vertex[T], t Vertex[T]) (*WeigthedDirectedGraph[T, N], error) {
+	return g.ShortestPathFromDistancesWithin(distances, s, t, util.DefaultTolerance)
+}
+
+/*
+Same as ShortestPathFromDistances, but an edge counts as attaining the distance of its head
+if it does so up to the given tolerance.
+*/
+func (g WeigthedDirectedGraph[T, N]) ShortestPathFromDistancesWithin(distances map[Vertex[T]]N, s Vertex[T], t Vertex[T], tol util.Tolerance) (*WeigthedDirectedGraph[T, N], error) {
+	path := WeigthedDirectedGraph[T, N]{[]Vertex[T]{t}, []*WeightedDirectedEdge[T, N]{}}
 	head := t
 	for {
 		if head.Node == s.Node {
 			return &path, nil
 		}
 
-		possibleNextEdges := util.FilterSlice(g.IncomingEdgesOf(head), func(e *WeightedDirectedEdge[T]) bool {
-			return e.Weight+distances[e.VertexFrom] == distances[head]
+		possibleNextEdges := util.FilterSlice(g.IncomingEdgesOf(head), func(e *WeightedDirectedEdge[T, N]) bool {
+			return attainsDistance(e, distances, head, tol)
 		})
 
 		if len(possibleNextEdges) == 0 {
@@ -49,8 +57,16 @@ Reconstructs a shortest path from the distances that Bellman-Ford-Moore returned
 Amongst all shotest paths it returns one that attains the smallest hop distance.
 Returns an error if no path exists or a negative cycle ocurrs.
 */
-func (g WeigthedDirectedGraph[T]) ShortestPathWithMinHopFromDistances(distances map[Vertex[T]]float64, s Vertex[T], t Vertex[T]) (*WeigthedDirectedGraph[T], error) {
-	shortestPathTree := WeigthedDirectedGraph[T]{[]Vertex[T]{t}, []*WeightedDirectedEdge[T]{}}
+func (g WeigthedDirectedGraph[T, N]) ShortestPathWithMinHopFromDistances(distances map[Vertex[T]]N, s Vertex[T], t Vertex[T]) (*WeigthedDirectedGraph[T, N], error) {
+	return g.ShortestPathWithMinHopFromDistancesWithin(distances, s, t, util.DefaultTolerance)
+}
+
+/*
+Same as ShortestPathWithMinHopFromDistances, but an edge counts as attaining the distance of its head
+if it does so up to the given tolerance.
+*/
+func (g WeigthedDirectedGraph[T, N]) ShortestPathWithMinHopFromDistancesWithin(distances map[Vertex[T]]N, s Vertex[T], t Vertex[T], tol util.Tolerance) (*WeigthedDirectedGraph[T, N], error) {
+	shortestPathTree := WeigthedDirectedGraph[T, N]{[]Vertex[T]{t}, []*WeightedDirectedEdge[T, N]{}}
 	heads := []Vertex[T]{t}
 
 	depths := make(map[Vertex[T]]int)
@@ -67,9 +83,8 @@ func (g WeigthedDirectedGraph[T]) ShortestPathWithMinHopFromDistances(distances
 		// fmt.Printf("current head: %v\n", head)
 		// fmt.Printf("current heads: %v\n", heads)
 
-		possibleNextEdges := util.FilterSlice(g.IncomingEdgesOf(head), func(e *WeightedDirectedEdge[T]) bool {
-			attainsDistance := e.Weight+distances[e.VertexFrom] == distances[head]
-			if !attainsDistance {
+		possibleNextEdges := util.FilterSlice(g.IncomingEdgesOf(head), func(e *WeightedDirectedEdge[T, N]) bool {
+			if !attainsDistance(e, distances, head, tol) {
 				return false
 			}
 			doesCreateNonPositiveCycle := false
@@ -118,3 +133,11 @@ func (g WeigthedDirectedGraph[T]) ShortestPathWithMinHopFromDistances(distances
 	// fmt.Printf("final shortestPathTree: \n%v", shortestPathTree)
 	return shortestPathTree.BFSShortestHopPathTo(s, t)
 }
+
+// Whether the edge e into head is tight, i.e. the distance of its tail plus its weight equals the distance of head.
+func attainsDistance[T Node, N util.Number](e *WeightedDirectedEdge[T, N], distances map[Vertex[T]]N, head Vertex[T], tol util.Tolerance) bool {
+	if distances[e.VertexFrom] == util.Infinity[N]() {
+		return false
+	}
+	return util.EqualWithin(tol, e.Weight+distances[e.VertexFrom], distances[head])
+}
@@ -11,12 +11,6 @@ type Basis struct {
 	YValues []float64
 }
 
-func (b Basis) injectY(y []float64) {
-	for i, bi := range b.Indices {
-		b.YValues[bi] = y[i]
-	}
-}
-
 func (b Basis) ToBasisIndex(index int) int {
 	return util.IndexOf(b.Indices, index)
 }
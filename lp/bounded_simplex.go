@@ -0,0 +1,296 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+Bounded-variable simplex method for max c@x s.t. A@x <= b, lower <= x <= upper.
+
+The bounds are not rows of the system but handled by the pivoting itself: with slack variables
+s >= 0 for A@x + s = b, every non-basic variable rests at one of its bounds, and a variable whose
+reduced cost makes it worth moving either reaches its other bound (a bound flip, without a change
+of basis) or drives a basic variable to one of its bounds, which then leaves the basis. The basis
+matrix only has one row per row of A, however many bounds there are.
+
+A nil lower means x >= 0 and a nil upper no upper bounds. The lower bounds must be finite, the upper
+bounds may be math.Inf(1). Rows whose right hand side cannot be met with x at its lower bounds are
+made feasible by artificial variables first, so no start basis is needed. y is the dual solution
+for the rows of A. Pivots follow Bland's rule for entering and leaving variables, so it never cycles.
+If the program is unbounded, the error is an *UnboundedError[float64] with a ray that respects the bounds.
+*/
+func BoundedSimplex(c []float64, A [][]float64, b []float64, lower []float64, upper []float64, opts ...Option) (x []float64, y []float64, optimalValue float64, err error) {
+	return BoundedSimplexContext(context.Background(), c, A, b, lower, upper, opts...)
+}
+
+// Same as BoundedSimplex, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func BoundedSimplexContext(ctx context.Context, c []float64, A [][]float64, b []float64, lower []float64, upper []float64, opts ...Option) (x []float64, y []float64, optimalValue float64, err error) {
+	n := len(c)
+	if len(A) == 0 || len(A[0]) != n || len(b) != len(A) {
+		return nil, nil, 0, ErrInvalidInput
+	}
+	if lower == nil {
+		lower = make([]float64, n)
+	}
+	if upper == nil {
+		upper = make([]float64, n)
+		for j := range upper {
+			upper[j] = math.Inf(1)
+		}
+	}
+	if len(lower) != n || len(upper) != n {
+		return nil, nil, 0, ErrInvalidInput
+	}
+	for j := range n {
+		if math.IsInf(lower[j], 0) || math.IsInf(upper[j], -1) {
+			return nil, nil, 0, ErrInvalidInput
+		}
+		if lower[j] > upper[j] {
+			return nil, nil, 0, ErrInfeasible
+		}
+	}
+
+	s := newBoundedSimplex(ctx, collectOptions(opts), A, b, lower, upper)
+
+	finish := func(status Status) {
+		s.o.observe(SolverFinished{Solver: "bounded simplex", Iterations: s.iterations, Status: status, Objective: optimalValue})
+	}
+
+	if len(s.artificials) > 0 {
+		// phase one: drive the artificial variables to zero
+		cost := make([]float64, len(s.columns))
+		for _, a := range s.artificials {
+			cost[a] = -1
+		}
+		if err := s.optimize(cost); err != nil {
+			finish(statusOf(err))
+			return nil, nil, 0, err
+		}
+		for _, a := range s.artificials {
+			if s.o.tolerance.Sign(s.value[a]) > 0 {
+				finish(StatusInfeasible)
+				return nil, nil, 0, ErrInfeasible
+			}
+			// an artificial variable that stays basic is held at zero by its bounds
+			s.upper[a] = 0
+			s.value[a] = 0
+		}
+	}
+
+	cost := make([]float64, len(s.columns))
+	copy(cost, c)
+	err = s.optimize(cost)
+	if err != nil {
+		finish(statusOf(err))
+		return nil, nil, 0, err
+	}
+	x = slices.Clone(s.value[:n])
+	optimalValue = util.DotProduct(c, x)
+	finish(StatusOptimal)
+	return x, s.y, optimalValue, nil
+}
+
+// The status a failed solve reports to the observer.
+func statusOf(err error) Status {
+	switch {
+	case errors.Is(err, util.ErrInterrupted):
+		return StatusInterrupted
+	case errors.Is(err, ErrUnbounded):
+		return StatusUnbounded
+	case errors.Is(err, ErrInfeasible):
+		return StatusInfeasible
+	case errors.Is(err, ErrMaxIterationsReached):
+		return StatusMaxIterations
+	}
+	return StatusFailed
+}
+
+type boundedSimplex struct {
+	ctx context.Context
+	o   options
+	m   int
+	n   int
+	b   []float64
+	// the columns of [A | I | -E], where I belongs to the slacks and -E to the artificial variables
+	columns [][]float64
+	lower   []float64
+	upper   []float64
+	// the current value of every variable, non-basic ones rest at one of their bounds
+	value       []float64
+	basis       []int
+	artificials []int
+	y           []float64
+	iterations  int
+}
+
+// Starts with x at its lower bounds and the slacks in the basis, or an artificial variable for rows that would need a negative slack.
+func newBoundedSimplex(ctx context.Context, o options, A [][]float64, b []float64, lower []float64, upper []float64) *boundedSimplex {
+	m, n := len(A), len(A[0])
+	s := &boundedSimplex{ctx: ctx, o: o, m: m, n: n, b: b}
+	for j := range n {
+		s.columns = append(s.columns, util.GetColumn(A, j))
+	}
+	s.lower = slices.Clone(lower)
+	s.upper = slices.Clone(upper)
+	s.value = slices.Clone(lower)
+
+	residual := util.VectorSub(b, util.MatMul(A, lower))
+	for i := range m {
+		e_i := make([]float64, m)
+		e_i[i] = 1
+		s.columns = append(s.columns, e_i)
+		s.lower = append(s.lower, 0)
+		s.upper = append(s.upper, math.Inf(1))
+		s.value = append(s.value, max(residual[i], 0))
+		s.basis = append(s.basis, n+i)
+	}
+	for i := range m {
+		if o.tolerance.Sign(residual[i]) >= 0 {
+			continue
+		}
+		// A_i@x + s_i - a_i = b_i with a_i = -residual_i replacing s_i in the basis
+		a := len(s.columns)
+		s.columns = append(s.columns, util.Neg(s.columns[n+i]))
+		s.lower = append(s.lower, 0)
+		s.upper = append(s.upper, math.Inf(1))
+		s.value = append(s.value, -residual[i])
+		s.basis[i] = a
+		s.artificials = append(s.artificials, a)
+	}
+	return s
+}
+
+// Runs the simplex with the given costs of all variables from the current basis until it is optimal.
+func (s *boundedSimplex) optimize(cost []float64) error {
+	tol := s.o.tolerance
+	f := s.o.float64Field()
+	for {
+		s.iterations++
+		if s.o.exhausted(s.iterations) {
+			return ErrMaxIterationsReached
+		}
+		if err := util.Interrupted(s.ctx); err != nil {
+			return err
+		}
+
+		B := make([][]float64, s.m)
+		for r := range s.m {
+			B[r] = make([]float64, s.m)
+			for k, j := range s.basis {
+				B[r][k] = s.columns[j][r]
+			}
+		}
+		lu, err := FactorizeLUOver(f, B)
+		if err != nil {
+			return err
+		}
+
+		// the basic variables follow from the non-basic ones
+		rhs := slices.Clone(s.b)
+		for j := range s.columns {
+			if !slices.Contains(s.basis, j) && s.value[j] != 0 {
+				rhs = util.VectorSub(rhs, util.ScalarMult(s.value[j], s.columns[j]))
+			}
+		}
+		for k, v := range lu.Solve(rhs) {
+			s.value[s.basis[k]] = v
+		}
+		s.y = lu.SolveTransposed(util.GetValues(cost, s.basis))
+
+		event := SimplexIteration{Leaving: -1, Entering: -1}
+		if s.o.tracing() {
+			event.Iteration = s.iterations
+			event.X = slices.Clone(s.value[:s.n])
+			event.Basis = slices.Clone(s.basis)
+			event.Objective = util.DotProduct(cost, s.value)
+		}
+
+		// Bland's rule: the first variable whose move improves the objective
+		entering, direction := -1, 0.0
+		for j := range s.columns {
+			if slices.Contains(s.basis, j) || s.lower[j] == s.upper[j] {
+				continue
+			}
+			reduced := cost[j] - util.DotProduct(s.y, s.columns[j])
+			if tol.Sign(reduced) > 0 && s.value[j] < s.upper[j] {
+				entering, direction = j, 1
+				break
+			}
+			if tol.Sign(reduced) < 0 && s.value[j] > s.lower[j] {
+				entering, direction = j, -1
+				break
+			}
+		}
+		if entering == -1 {
+			s.o.observe(event)
+			return nil
+		}
+		event.Entering = entering
+
+		// moving the entering variable by t*direction moves the basic ones by -t*direction*alpha
+		alpha := lu.Solve(s.columns[entering])
+		step := s.upper[entering] - s.lower[entering]
+		leaving := -1
+		for k, j := range s.basis {
+			rate := -direction * alpha[k]
+			var limit float64
+			switch {
+			case tol.Sign(rate) < 0:
+				limit = (s.value[j] - s.lower[j]) / -rate
+			case tol.Sign(rate) > 0 && !math.IsInf(s.upper[j], 1):
+				limit = (s.upper[j] - s.value[j]) / rate
+			default:
+				continue
+			}
+			limit = max(limit, 0)
+			if tol.Less(limit, step) || leaving != -1 && tol.Equal(limit, step) && j < s.basis[leaving] {
+				step, leaving = limit, k
+			}
+		}
+
+		if math.IsInf(step, 1) {
+			s.o.observe(event)
+			ray := make([]float64, s.n)
+			if entering < s.n {
+				ray[entering] = direction
+			}
+			for k, j := range s.basis {
+				if j < s.n {
+					ray[j] = -direction * alpha[k]
+				}
+			}
+			return &UnboundedError[float64]{X: slices.Clone(s.value[:s.n]), Ray: ray}
+		}
+
+		if s.o.tracing() {
+			event.Direction = util.ScalarMult(-direction, alpha)
+			event.StepLength = step
+		}
+		s.value[entering] += direction * step
+		for k, j := range s.basis {
+			s.value[j] -= direction * alpha[k] * step
+		}
+		if leaving == -1 {
+			// bound flip
+			s.value[entering] = s.upper[entering]
+			if direction < 0 {
+				s.value[entering] = s.lower[entering]
+			}
+		} else {
+			j := s.basis[leaving]
+			event.Leaving = j
+			if -direction*alpha[leaving] < 0 {
+				s.value[j] = s.lower[j]
+			} else {
+				s.value[j] = s.upper[j]
+			}
+			s.basis[leaving] = entering
+		}
+		s.o.observe(event)
+	}
+}
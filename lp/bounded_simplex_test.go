@@ -0,0 +1,90 @@
+package lp_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// An option that stores the status of the SolverFinished event in status.
+func recordStatus(status *lp.Status) lp.Option {
+	return lp.WithObserver(trace.ObserverFunc(func(e trace.Event) {
+		if e, ok := e.(lp.SolverFinished); ok {
+			*status = e.Status
+		}
+	}))
+}
+
+func TestBoundedSimplex(t *testing.T) {
+	// the fractional knapsack: take the items by value per weight, the last one partially
+	values := []float64{60, 100, 120}
+	weights := []float64{10, 20, 30}
+	x, y, optimalValue, err := lp.BoundedSimplex(values, [][]float64{weights}, []float64{50}, nil, []float64{1, 1, 1})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{1, 1, 2.0 / 3}, x, epsilon)
+	assert.InDelta(t, 240, optimalValue, epsilon)
+	assert.InDeltaSlice(t, []float64{4}, y, epsilon)
+
+	// the lower bounds x_1 >= 2, x_2 >= 2 violate x_1 + x_2 <= 3
+	var status lp.Status
+	_, _, _, err = lp.BoundedSimplex([]float64{1, 1}, [][]float64{{1, 1}}, []float64{3}, []float64{2, 2}, nil, recordStatus(&status))
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, status)
+
+	// -x_1 + x_2 <= 1 with x_1 unbounded above
+	_, _, _, err = lp.BoundedSimplex([]float64{1, 1}, [][]float64{{-1, 1}}, []float64{1}, nil, []float64{math.Inf(1), 5})
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+	var unbounded *lp.UnboundedError[float64]
+	assert.True(t, errors.As(err, &unbounded))
+	assert.LessOrEqual(t, -unbounded.Ray[0]+unbounded.Ray[1], epsilon)
+	assert.Greater(t, unbounded.Ray[0]+unbounded.Ray[1], epsilon)
+
+	// a negative right hand side needs the artificial variables: x_1 + x_2 >= 4
+	x, _, optimalValue, err = lp.BoundedSimplex([]float64{-2, -3}, [][]float64{{-1, -1}}, []float64{-4}, []float64{1, 0}, []float64{3, 3})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{3, 1}, x, epsilon)
+	assert.InDelta(t, -9, optimalValue, epsilon)
+}
+
+func TestBoundedSimplexAgreesWithMaximize(t *testing.T) {
+	random := rand.New(rand.NewSource(21))
+	for range 30 {
+		m, n := 1+random.Intn(6), 1+random.Intn(8)
+		c, A, b := randomLP(random, m, n, 6)
+		upper := make([]float64, n)
+		for j := range n {
+			upper[j] = float64(1 + random.Intn(5))
+		}
+
+		// the same bounds as explicit rows x_j <= u_j
+		rows, rhs := slices.Clone(A), slices.Clone(b)
+		for j := range n {
+			row := make([]float64, n)
+			row[j] = 1
+			rows = append(rows, row)
+			rhs = append(rhs, upper[j])
+		}
+		_, expected, expectedErr := lp.Maximize(c, rows, rhs)
+
+		x, _, optimalValue, err := lp.BoundedSimplex(c, A, b, nil, upper)
+		assert.Equal(t, expectedErr, err)
+		if expectedErr != nil {
+			continue
+		}
+		assert.InDelta(t, expected, optimalValue, 1e-6)
+		for i := range m {
+			assert.LessOrEqual(t, util.DotProduct(A[i], x), b[i]+1e-6)
+		}
+		for j := range n {
+			assert.GreaterOrEqual(t, x[j], -1e-6)
+			assert.LessOrEqual(t, x[j], upper[j]+1e-6)
+		}
+	}
+}
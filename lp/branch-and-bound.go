@@ -1,2 +1,352 @@
 package lp
 
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrNodeLimitReached = errors.New("maximum number of branch-and-bound nodes reached")
+
+// Decides on which fractional variable of a relaxation's solution BranchAndBound branches.
+type BranchingRule int
+
+const (
+	// the fractional variable with the smallest index
+	FirstFractional BranchingRule = iota
+	// the variable whose fractional part is closest to 1/2
+	MostFractional
+	// the variable whose fractional part is closest to 0 or 1
+	LeastFractional
+)
+
+// Decides which open node BranchAndBound solves next.
+type NodeSelection int
+
+const (
+	// the node with the best bound, which keeps the number of solved nodes small
+	BestBound NodeSelection = iota
+	// the most recently created node, which finds incumbents early and needs little memory
+	DepthFirst
+)
+
+/*
+The outcome of BranchAndBound. X is the best integral solution found (the incumbent), or nil if
+there is none, and Objective its value. Bound is an upper bound on the optimal value of the mixed
+integer program and Gap the relative gap (Bound - Objective) / max(1, |Objective|) between both.
+If the search completed, Bound equals Objective up to the gap tolerance.
+*/
+type MIPResult struct {
+	X         []float64
+	Objective float64
+	Bound     float64
+	Gap       float64
+	Nodes     int
+	Status    Status
+}
+
+type bnbNode struct {
+	// the rows added to the root's system by branching and cutting, in the order they were added
+	rows [][]float64
+	rhs  []float64
+	// optimal basis of the parent, a dual feasible basis of this node, see bnbSolver.system
+	basis []int
+	// the objective value of the parent's relaxation, an upper bound on this node
+	bound float64
+	depth int
+}
+
+/*
+Mixed integer program max c@x s.t. A@x <= b, x >= 0, x_j integral for all j with integer[j].
+
+Every node of the search tree is a linear relaxation. The root is solved by PhaseOne and Simplex.
+A child only adds one bound to its parent, so the parent's optimal basis stays dual feasible and the
+child is re-optimized by DualSimplex starting from it. Use WithBranchingRule, WithNodeSelection,
+WithNodeLimit and WithGapTolerance to configure the search, and WithCuts to strengthen the relaxation
+of every node by Gomory cuts before branching on it.
+*/
+func BranchAndBound(c []float64, A [][]float64, b []float64, integer []bool, opts ...Option) (result MIPResult, err error) {
+	return BranchAndBoundContext(context.Background(), c, A, b, integer, opts...)
+}
+
+/*
+Same as BranchAndBound, but stops once ctx is done. The result then holds the incumbent found so far
+and the current bound, and the error matches util.ErrInterrupted.
+*/
+func BranchAndBoundContext(ctx context.Context, c []float64, A [][]float64, b []float64, integer []bool, opts ...Option) (result MIPResult, err error) {
+	o := collectOptions(opts)
+	if len(A) == 0 || len(A[0]) != len(c) || len(b) != len(A) || len(integer) != len(c) {
+		return MIPResult{}, ErrInvalidInput
+	}
+
+	s := bnbSolver{ctx: ctx, o: o, c: c, A: A, b: b, integer: integer}
+	s.incumbent = math.Inf(-1)
+
+	root := bnbNode{bound: math.Inf(1)}
+	open := []bnbNode{root}
+
+	finish := func(status Status) {
+		result.X = s.best
+		result.Objective = s.incumbent
+		result.Bound = s.incumbent
+		for _, node := range open {
+			result.Bound = max(result.Bound, node.bound)
+		}
+		result.Gap = gap(result.Objective, result.Bound)
+		result.Nodes = s.nodes
+		result.Status = status
+		o.observe(SolverFinished{Solver: "branch and bound", Iterations: s.nodes, Status: status, Objective: result.Objective})
+	}
+
+	for len(open) > 0 {
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted)
+			return result, err
+		}
+		if o.nodeLimit > 0 && s.nodes >= o.nodeLimit {
+			finish(StatusMaxIterations)
+			return result, ErrNodeLimitReached
+		}
+
+		k := len(open) - 1
+		if o.nodeSelection == BestBound {
+			for i, node := range open {
+				// ties go to the deeper, more recent node
+				if node.bound >= open[k].bound {
+					k = i
+				}
+			}
+		}
+		node := open[k]
+		open = slices.Delete(open, k, k+1)
+
+		if s.prunable(node.bound) {
+			continue
+		}
+
+		children, err := s.process(node)
+		if errors.Is(err, util.ErrInterrupted) {
+			open = append(open, node)
+			finish(StatusInterrupted)
+			return result, err
+		}
+		if errors.Is(err, ErrUnbounded) {
+			finish(StatusUnbounded)
+			return result, err
+		}
+		if err != nil {
+			finish(StatusFailed)
+			return result, err
+		}
+		open = append(open, children...)
+	}
+
+	if s.best == nil {
+		finish(StatusInfeasible)
+		return result, ErrInfeasible
+	}
+	finish(StatusOptimal)
+	return result, nil
+}
+
+type bnbSolver struct {
+	ctx     context.Context
+	o       options
+	c       []float64
+	A       [][]float64
+	b       []float64
+	integer []bool
+
+	nodes     int
+	best      []float64
+	incumbent float64
+}
+
+// Whether a node with the given bound cannot improve the incumbent by more than the gap tolerance.
+func (s *bnbSolver) prunable(bound float64) bool {
+	if s.best == nil {
+		return false
+	}
+	return s.o.tolerance.Compare(bound, s.incumbent) <= 0 || gap(s.incumbent, bound) <= s.o.gapTolerance
+}
+
+func gap(objective float64, bound float64) float64 {
+	if math.IsInf(objective, -1) || math.IsInf(bound, 1) {
+		return math.Inf(1)
+	}
+	return (bound - objective) / max(1, math.Abs(objective))
+}
+
+/*
+The relaxation of a node in natural form: the rows of A, then -I for x >= 0 and finally the rows
+added by branching and cutting. Rows are only ever appended, so the indices of a parent's basis
+keep their meaning in its children.
+*/
+func (s *bnbSolver) system(node bnbNode) (A [][]float64, b []float64) {
+	n := len(s.c)
+	A = slices.Concat(s.A, util.NegMatrix(util.IdentityMatrix(n)), node.rows)
+	b = slices.Concat(s.b, make([]float64, n), node.rhs)
+	return A, b
+}
+
+// The child of node with the additional row a@x <= rhs.
+func (node bnbNode) child(a []float64, rhs float64, basis []int, bound float64) bnbNode {
+	return bnbNode{
+		rows:  append(slices.Clone(node.rows), a),
+		rhs:   append(slices.Clone(node.rhs), rhs),
+		basis: basis,
+		bound: bound,
+		depth: node.depth + 1,
+	}
+}
+
+// Solves the relaxation of node, updates the incumbent and returns the children to explore.
+func (s *bnbSolver) process(node bnbNode) (children []bnbNode, err error) {
+	s.nodes++
+	A, b := s.system(node)
+	x, value, basis, feasible, err := s.relax(A, b, node.basis)
+	if err != nil {
+		return nil, err
+	}
+
+	// cuts are only valid within the subtree of node, so they are added as rows of node that its children inherit
+	cuts := 0
+	for round := 0; round < s.o.cutRounds && feasible && !s.prunable(value) && s.branchingVariable(x) != -1; round++ {
+		cutsA, cutsB, err := GomoryCuts(A, b, basis, s.integer, s.o.cutFamily, WithTolerance(s.o.tolerance))
+		if err != nil || len(cutsA) == 0 {
+			break
+		}
+		node.rows = slices.Concat(node.rows, cutsA)
+		node.rhs = slices.Concat(node.rhs, cutsB)
+		cuts += len(cutsA)
+		A, b = s.system(node)
+		x, value, basis, feasible, err = s.relax(A, b, basis)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	event := BranchAndBoundNode{Node: s.nodes, Depth: node.depth, Relaxation: value, Variable: -1, Cuts: cuts}
+	defer func() {
+		event.Incumbent = s.incumbent
+		s.o.observe(event)
+	}()
+
+	if !feasible {
+		event.Outcome = "infeasible"
+		return nil, nil
+	}
+	if s.prunable(value) {
+		event.Outcome = "pruned"
+		return nil, nil
+	}
+
+	j := s.branchingVariable(x)
+	if j == -1 {
+		event.Outcome = "integral"
+		for j := range x {
+			if s.integer[j] {
+				x[j] = math.Round(x[j])
+			}
+		}
+		s.best = x
+		s.incumbent = util.DotProduct(s.c, x)
+		return nil, nil
+	}
+
+	event.Outcome = "branched"
+	event.Variable = j
+	e_j := make([]float64, len(x))
+	e_j[j] = 1
+	// x_j <= floor(x_j) and -x_j <= -ceil(x_j)
+	down := node.child(e_j, math.Floor(x[j]), basis, value)
+	up := node.child(util.Neg(e_j), -math.Ceil(x[j]), basis, value)
+	// the child listed last is explored first by DepthFirst: go in the direction x_j is closer to
+	if x[j]-math.Floor(x[j]) < 0.5 {
+		return []bnbNode{up, down}, nil
+	}
+	return []bnbNode{down, up}, nil
+}
+
+/*
+Solves max c@x s.t. A@x <= b, where A contains the rows -I for x >= 0 directly after the original rows.
+With a dual feasible start basis the dual simplex is used, otherwise PhaseOne and Simplex.
+*/
+func (s *bnbSolver) relax(A [][]float64, b []float64, startbasis []int) (x []float64, value float64, basis []int, feasible bool, err error) {
+	inner := []Option{WithTolerance(s.o.tolerance)}
+	if startbasis != nil {
+		x, _, _, basis, err = DualSimplexContext(s.ctx, A, b, s.c, startbasis, inner...)
+		switch {
+		case err == nil:
+			return x, util.DotProduct(s.c, x), basis, true, nil
+		case errors.Is(err, ErrUnbounded):
+			// the dual is unbounded, so the relaxation is infeasible
+			return nil, 0, nil, false, nil
+		case errors.Is(err, util.ErrInterrupted):
+			return nil, 0, nil, false, err
+		}
+		// the dual simplex got stuck, solve the node from scratch
+	}
+
+	m := len(s.A)
+	n := len(s.c)
+	r := len(A) - m - n
+	rows := slices.Concat(A[:m], A[m+n:])
+	rhs := slices.Concat(b[:m], b[m+n:])
+	phaseOneBasis, feasible, _, err := PhaseOneContext(s.ctx, rows, rhs, false, inner...)
+	if err != nil || !feasible {
+		return nil, 0, nil, false, err
+	}
+	// PhaseOne refers to [A; added rows; -I], translate to [A; -I; added rows]
+	basis = make([]int, len(phaseOneBasis))
+	for k, i := range phaseOneBasis {
+		switch {
+		case i < m:
+			basis[k] = i
+		case i < m+r:
+			basis[k] = i + n
+		default:
+			basis[k] = i - r
+		}
+	}
+	slices.Sort(basis)
+
+	x, _, value, basis, err = SimplexContext(s.ctx, s.c, A, b, basis, inner...)
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+	return x, value, basis, true, nil
+}
+
+/*
+The integer variable to branch on according to the branching rule, or -1 if x is integral.
+Integrality is tested with the absolute tolerance only, as the relative one would let large
+fractional values pass.
+*/
+func (s *bnbSolver) branchingVariable(x []float64) int {
+	selected := -1
+	score := 0.0
+	for j, v := range x {
+		if !s.integer[j] || s.o.tolerance.IsZero(v-math.Round(v)) {
+			continue
+		}
+		// distance to the nearest integer, between 0 and 1/2
+		fractionality := math.Abs(v - math.Round(v))
+		switch s.o.branchingRule {
+		case FirstFractional:
+			return j
+		case MostFractional:
+			if selected == -1 || fractionality > score {
+				selected, score = j, fractionality
+			}
+		case LeastFractional:
+			if selected == -1 || fractionality < score {
+				selected, score = j, fractionality
+			}
+		}
+	}
+	return selected
+}
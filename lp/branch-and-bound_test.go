@@ -0,0 +1,148 @@
+package lp_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchAndBound(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+	}
+	b := []float64{24, 6}
+	c := []float64{5, 4}
+
+	for _, rule := range []lp.BranchingRule{lp.FirstFractional, lp.MostFractional, lp.LeastFractional} {
+		for _, selection := range []lp.NodeSelection{lp.BestBound, lp.DepthFirst} {
+			result, err := lp.BranchAndBound(c, A, b, []bool{true, true}, lp.WithBranchingRule(rule), lp.WithNodeSelection(selection))
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{4, 0}, result.X)
+			assert.InDelta(t, 20, result.Objective, epsilon)
+			assert.InDelta(t, 20, result.Bound, epsilon)
+			assert.InDelta(t, 0, result.Gap, epsilon)
+			assert.Equal(t, lp.StatusOptimal, result.Status)
+		}
+	}
+
+	// only x_1 integral: the relaxation optimum (3, 1.5) already is feasible
+	result, err := lp.BranchAndBound(c, A, b, []bool{true, false})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{3, 1.5}, result.X, epsilon)
+	assert.Equal(t, 1, result.Nodes)
+
+	// x = 1e10 + 0.5 is within the relative tolerance of an integer, but not integral
+	result, err = lp.BranchAndBound([]float64{1}, [][]float64{{2}}, []float64{2e10 + 1}, []bool{true})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1e10}, result.X)
+	assert.Equal(t, 1e10, result.Objective)
+	assert.Equal(t, lp.StatusOptimal, result.Status)
+}
+
+func TestBranchAndBoundInfeasible(t *testing.T) {
+	// 0.5 <= x <= 0.5
+	result, err := lp.BranchAndBound([]float64{1}, [][]float64{{1}, {-1}}, []float64{0.5, -0.5}, []bool{true})
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, result.Status)
+}
+
+func TestBranchAndBoundKnapsack(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	n := 10
+	weights := make([]float64, n)
+	values := make([]float64, n)
+	A := [][]float64{weights}
+	b := []float64{0}
+	for j := range n {
+		weights[j] = float64(5 + random.Intn(20))
+		values[j] = float64(5 + random.Intn(20))
+		b[0] += weights[j] / 2
+		// x_j <= 1
+		row := make([]float64, n)
+		row[j] = 1
+		A = append(A, row)
+		b = append(b, 1)
+	}
+	integer := make([]bool, n)
+	for j := range integer {
+		integer[j] = true
+	}
+
+	best := 0.0
+	for subset := range 1 << n {
+		weight, value := 0.0, 0.0
+		for j := range n {
+			if subset&(1<<j) != 0 {
+				weight += weights[j]
+				value += values[j]
+			}
+		}
+		if weight <= b[0] {
+			best = max(best, value)
+		}
+	}
+
+	for _, selection := range []lp.NodeSelection{lp.BestBound, lp.DepthFirst} {
+		result, err := lp.BranchAndBound(values, A, b, integer, lp.WithNodeSelection(selection), lp.WithBranchingRule(lp.MostFractional))
+		assert.NoError(t, err)
+		assert.InDelta(t, best, result.Objective, epsilon)
+		assert.InDelta(t, best, util.DotProduct(values, result.X), epsilon)
+		assert.LessOrEqual(t, util.DotProduct(weights, result.X), b[0]+epsilon)
+	}
+
+	result, err := lp.BranchAndBound(values, A, b, integer, lp.WithCuts(lp.GomoryMixedInteger, 2))
+	assert.NoError(t, err)
+	assert.InDelta(t, best, result.Objective, epsilon)
+	assert.LessOrEqual(t, util.DotProduct(weights, result.X), b[0]+epsilon)
+}
+
+func TestBranchAndBoundLimits(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+	}
+	b := []float64{24, 6}
+	c := []float64{5, 4}
+	integer := []bool{true, true}
+
+	result, err := lp.BranchAndBound(c, A, b, integer, lp.WithNodeLimit(1))
+	assert.ErrorIs(t, err, lp.ErrNodeLimitReached)
+	assert.Nil(t, result.X)
+	assert.InDelta(t, 21, result.Bound, epsilon)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = lp.BranchAndBoundContext(ctx, c, A, b, integer)
+	assert.ErrorIs(t, err, util.ErrInterrupted)
+	assert.Equal(t, lp.StatusInterrupted, result.Status)
+
+	var out bytes.Buffer
+	_, err = lp.BranchAndBound(c, A, b, integer, lp.WithObserver(lp.NewTableObserver(&out)))
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "branched")
+	assert.Contains(t, out.String(), "BRANCH AND BOUND")
+}
+
+func TestModelWithIntegerVariables(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddIntegerVariable("x", 0.5, 10)
+	y := m.AddIntegerVariable("y", -3, 3)
+	m.AddConstraint("c1", []lp.Term{lp.T(2, x), lp.T(2, y)}, lp.LessEqual, 7)
+	m.AddConstraint("c2", []lp.Term{lp.T(1, x), lp.T(-1, y)}, lp.LessEqual, 2.5)
+	m.SetObjective(lp.Minimization, []lp.Term{lp.T(-1, x), lp.T(-2, y)})
+
+	solution, err := m.Solve()
+	assert.NoError(t, err)
+	// x >= 1 and 2x + 2y <= 7 gives x + y <= 3
+	assert.InDelta(t, 1, solution.Value(x), epsilon)
+	assert.InDelta(t, 2, solution.Value(y), epsilon)
+	assert.InDelta(t, -5, solution.Objective, epsilon)
+	assert.InDelta(t, -5, solution.Bound, epsilon)
+	assert.InDelta(t, 0, solution.Gap, epsilon)
+}
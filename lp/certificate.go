@@ -0,0 +1,56 @@
+package lp
+
+/*
+Returned by Simplex and RevisedSimplex instead of ErrUnbounded, which it matches by errors.Is, to
+certify that max c@x s.t. A@x <= b is unbounded: X is a feasible vertex and Ray a direction with
+A@Ray <= 0 and c@Ray > 0, so X + t*Ray is feasible for all t >= 0 and its objective grows without limit.
+Use errors.As with a *UnboundedError[float64] (or the field's type for SimplexOver) to get it.
+*/
+type UnboundedError[T any] struct {
+	X   []T
+	Ray []T
+}
+
+func (e *UnboundedError[T]) Error() string {
+	return ErrUnbounded.Error()
+}
+
+func (e *UnboundedError[T]) Unwrap() error {
+	return ErrUnbounded
+}
+
+/*
+The counterpart of UnboundedError returned by DualSimplex when min y@b s.t. y@A = c, y >= 0 is unbounded:
+Y is the dual feasible solution of the last basis and Ray satisfies Ray >= 0, Ray@A = 0 and Ray@b < 0,
+so Y + t*Ray is dual feasible for all t >= 0 and its objective falls without limit.
+Ray is a Farkas certificate as returned by PhaseOne, proving that the primal A@x <= b is infeasible.
+*/
+type DualUnboundedError[T any] struct {
+	Y   []T
+	Ray []T
+}
+
+func (e *DualUnboundedError[T]) Error() string {
+	return ErrUnbounded.Error()
+}
+
+func (e *DualUnboundedError[T]) Unwrap() error {
+	return ErrUnbounded
+}
+
+/*
+Returned by Solver instead of ErrInfeasible, which it matches by errors.Is, to certify that
+A@x <= b, x >= 0 is infeasible: Farkas is a certificate as returned by PhaseOne, a vector y >= 0 with
+y@A >= 0 and y@b < 0. Use errors.As with a *InfeasibleError[float64] to get it.
+*/
+type InfeasibleError[T any] struct {
+	Farkas []T
+}
+
+func (e *InfeasibleError[T]) Error() string {
+	return ErrInfeasible.Error()
+}
+
+func (e *InfeasibleError[T]) Unwrap() error {
+	return ErrInfeasible
+}
@@ -0,0 +1,68 @@
+package lp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnboundedError(t *testing.T) {
+	A := [][]float64{
+		{1, 1, -1, 0},
+		{6, 5, 0, -1},
+		{0, 0, -1, 0},
+		{0, 0, 0, -1},
+		{-1, 0, 0, 0},
+		{0, -1, 0, 0},
+	}
+	b := []float64{5, 10, 0, 0, 0, 0}
+	c := []float64{36, 30, -3, -4}
+	startbasis := []int{2, 3, 4, 5}
+
+	solvers := map[string]func() error{
+		"simplex": func() error {
+			_, _, _, _, err := lp.Simplex(c, A, b, startbasis)
+			return err
+		},
+		"revised simplex": func() error {
+			_, _, _, _, err := lp.RevisedSimplex(c, A, b, startbasis)
+			return err
+		},
+	}
+	for name, solve := range solvers {
+		err := solve()
+		assert.ErrorIs(t, err, lp.ErrUnbounded, name)
+		var unbounded *lp.UnboundedError[float64]
+		assert.True(t, errors.As(err, &unbounded), name)
+
+		for i, row := range A {
+			assert.LessOrEqual(t, util.DotProduct(row, unbounded.X), b[i]+epsilon, name)
+			assert.LessOrEqual(t, util.DotProduct(row, unbounded.Ray), epsilon, name)
+		}
+		assert.Greater(t, util.DotProduct(c, unbounded.Ray), epsilon, name)
+	}
+}
+
+func TestDualUnboundedError(t *testing.T) {
+	// x <= 1 and x >= 2
+	A := [][]float64{
+		{1},
+		{-1},
+		{-1},
+	}
+	b := []float64{1, -2, 0}
+	c := []float64{-1}
+
+	// max -x is dual feasible at x = 0
+	_, _, _, _, err := lp.DualSimplex(A, b, c, []int{2})
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+	var unbounded *lp.DualUnboundedError[float64]
+	assert.True(t, errors.As(err, &unbounded))
+
+	assert.InDeltaSlice(t, c, util.MatMul(util.Transpose(A), unbounded.Y), epsilon)
+	assertFarkas(t, A, b, unbounded.Ray)
+	assert.InDeltaSlice(t, []float64{0}, util.MatMul(util.Transpose(A), unbounded.Ray), epsilon)
+}
@@ -0,0 +1,83 @@
+package lp
+
+import "math"
+
+const (
+	// see factorizeCholesky
+	choleskyPivotTolerance = 1e-30
+	choleskySkippedPivot   = 1e128
+)
+
+/*
+Cholesky factorization A = L@L^T of a symmetric positive definite matrix A, as used by InteriorPoint
+for its normal equations. It needs half the work of an LU factorization and no pivoting.
+*/
+type Cholesky struct {
+	// L on and below the diagonal
+	l [][]float64
+}
+
+/*
+Factorizes A, reading only its lower triangle. If a pivot is not positive, A is not positive
+definite (at least not numerically) and ErrSingularMatrix is returned.
+*/
+func FactorizeCholesky(A [][]float64) (*Cholesky, error) {
+	return factorizeCholesky(A, false)
+}
+
+/*
+Tolerant factorizes the way interior point methods need near the optimum, where the normal equations
+become ill-conditioned: a pivot that is not larger than a tiny fraction of the diagonal entry it
+comes from is replaced by a huge one, which effectively drops its row and column from the system.
+*/
+func factorizeCholesky(A [][]float64, tolerant bool) (*Cholesky, error) {
+	N := len(A)
+	if N == 0 || len(A[0]) != N {
+		return nil, ErrInvalidInput
+	}
+
+	l := make([][]float64, N)
+	for i := range N {
+		l[i] = make([]float64, i+1)
+		for j := 0; j <= i; j++ {
+			sum := A[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if tolerant && sum <= choleskyPivotTolerance*A[i][i] {
+					sum = choleskySkippedPivot
+				}
+				if sum <= 0 {
+					return nil, ErrSingularMatrix
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return &Cholesky{l: l}, nil
+}
+
+// Solves A@x = b.
+func (d *Cholesky) Solve(b []float64) (x []float64) {
+	N := len(d.l)
+	x = make([]float64, N)
+	// forward substitution L@z = b
+	for i := 0; i < N; i++ {
+		x[i] = b[i]
+		for j := 0; j < i; j++ {
+			x[i] -= d.l[i][j] * x[j]
+		}
+		x[i] /= d.l[i][i]
+	}
+	// back substitution L^T@x = z
+	for i := N - 1; i >= 0; i-- {
+		for j := i + 1; j < N; j++ {
+			x[i] -= d.l[j][i] * x[j]
+		}
+		x[i] /= d.l[i][i]
+	}
+	return x
+}
@@ -0,0 +1,571 @@
+package lp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+Reads a linear or mixed integer program in CPLEX LP format into a model. Supported are the sections
+Maximize/Minimize, Subject To, Bounds, Generals and Binaries (and their usual abbreviations) up to
+End, where keywords are case-insensitive and must start a line. Constraints and bounds may be ranges
+like -5 <= x + y <= 5, and inf or infinity stand for an infinite bound. Variables are non-negative
+unless bounded otherwise and appear in the model in the order in which they occur in the file.
+A constant in the objective is not supported, constants in constraints move to the right hand side.
+*/
+func ReadLP(r io.Reader) (*Model, error) {
+	tokens, err := lpTokens(r)
+	if err != nil {
+		return nil, err
+	}
+	p := lpReader{tokens: tokens, m: NewModel(), variables: map[string]*Variable{}}
+
+	section := ""
+	for {
+		if p.pos == len(p.tokens) {
+			return nil, fmt.Errorf("%w: missing End", ErrInvalidFormat)
+		}
+		if keyword := p.section(); keyword != "" {
+			section = keyword
+			if section == "end" {
+				return p.m, nil
+			}
+			if section == "maximize" || section == "minimize" {
+				err = p.objective(section)
+			}
+		} else {
+			switch section {
+			case "subject to":
+				err = p.constraint()
+			case "bounds":
+				err = p.bound()
+			case "generals", "binaries":
+				err = p.integer(section == "binaries")
+			default:
+				err = p.errorf("data outside of a section")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+type lpTokenKind int
+
+const (
+	lpName lpTokenKind = iota
+	lpNumber
+	lpSign
+	lpRelation
+	lpColon
+	lpEnd
+)
+
+type lpToken struct {
+	kind     lpTokenKind
+	text     string
+	value    float64
+	relation Relation
+	line     int
+	// whether the token is the first of its line, which keywords have to be
+	first bool
+}
+
+// Characters that end a name, besides whitespace.
+const lpSpecial = "+-<>=:\\*^"
+
+// Splits the file into tokens, dropping comments from a backslash to the end of the line.
+func lpTokens(r io.Reader) ([]lpToken, error) {
+	var tokens []lpToken
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if k := strings.IndexByte(text, '\\'); k >= 0 {
+			text = text[:k]
+		}
+		first := true
+		for i := 0; i < len(text); {
+			c := text[i]
+			token := lpToken{line: line, first: first}
+			j := i + 1
+			switch {
+			case unicode.IsSpace(rune(c)):
+				i++
+				continue
+			case c == '+' || c == '-':
+				token.kind, token.value = lpSign, 1
+				if c == '-' {
+					token.value = -1
+				}
+			case c == ':':
+				token.kind = lpColon
+			case c == '<' || c == '>' || c == '=':
+				token.kind = lpRelation
+				if j < len(text) && strings.IndexByte("<>=", text[j]) >= 0 {
+					j++
+				}
+				switch text[i:j] {
+				case "<", "<=", "=<":
+					token.relation = LessEqual
+				case ">", ">=", "=>":
+					token.relation = GreaterEqual
+				case "=":
+					token.relation = Equal
+				default:
+					return nil, fmt.Errorf("%w: line %v: unknown operator %v", ErrInvalidFormat, line, text[i:j])
+				}
+			case c >= '0' && c <= '9' || c == '.':
+				token.kind = lpNumber
+				for j < len(text) && (text[j] >= '0' && text[j] <= '9' || text[j] == '.') {
+					j++
+				}
+				// an exponent, unless the e starts a name like in 3 e1
+				if j < len(text) && (text[j] == 'e' || text[j] == 'E') {
+					k := j + 1
+					if k < len(text) && (text[k] == '+' || text[k] == '-') {
+						k++
+					}
+					if k < len(text) && text[k] >= '0' && text[k] <= '9' {
+						for j = k; j < len(text) && text[j] >= '0' && text[j] <= '9'; j++ {
+						}
+					}
+				}
+				value, err := strconv.ParseFloat(text[i:j], 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %v: %v is not a number", ErrInvalidFormat, line, text[i:j])
+				}
+				token.value = value
+			case strings.IndexByte(lpSpecial, c) >= 0:
+				return nil, fmt.Errorf("%w: line %v: unexpected %c", ErrInvalidFormat, line, c)
+			default:
+				token.kind = lpName
+				for j < len(text) && !unicode.IsSpace(rune(text[j])) && strings.IndexByte(lpSpecial, text[j]) < 0 {
+					j++
+				}
+			}
+			token.text = text[i:j]
+			tokens = append(tokens, token)
+			first = false
+			i = j
+		}
+	}
+	return tokens, scanner.Err()
+}
+
+type lpReader struct {
+	tokens    []lpToken
+	pos       int
+	m         *Model
+	variables map[string]*Variable
+}
+
+func (p *lpReader) errorf(format string, args ...any) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("%w: line %v: %v", ErrInvalidFormat, line, fmt.Sprintf(format, args...))
+}
+
+// The token at offset from the current position, of kind lpEnd past the last one.
+func (p *lpReader) peek(offset int) lpToken {
+	if p.pos+offset >= len(p.tokens) {
+		return lpToken{kind: lpEnd}
+	}
+	return p.tokens[p.pos+offset]
+}
+
+// The keyword of the section that starts at the current position, which it consumes, or "".
+func (p *lpReader) section() string {
+	t := p.peek(0)
+	if t.kind != lpName || !t.first {
+		return ""
+	}
+	word := strings.ToLower(t.text)
+	keyword, length := "", 1
+	switch word {
+	case "maximize", "maximum", "max":
+		keyword = "maximize"
+	case "minimize", "minimum", "min":
+		keyword = "minimize"
+	case "st", "st.", "s.t.":
+		keyword = "subject to"
+	case "subject", "such":
+		next := p.peek(1)
+		if next.kind == lpName && strings.ToLower(next.text) == map[string]string{"subject": "to", "such": "that"}[word] {
+			keyword, length = "subject to", 2
+		}
+	case "bounds", "bound":
+		keyword = "bounds"
+	case "generals", "general", "gen":
+		keyword = "generals"
+	case "binaries", "binary", "bin":
+		keyword = "binaries"
+	case "end":
+		keyword = "end"
+	}
+	if keyword != "" {
+		p.pos += length
+	}
+	return keyword
+}
+
+// The variable of the given name, which is added to the model when it first occurs.
+func (p *lpReader) variable(name string) *Variable {
+	v, ok := p.variables[name]
+	if !ok {
+		v = p.m.AddNonNegativeVariable(name)
+		p.variables[name] = v
+	}
+	return v
+}
+
+// Consumes an optional name: label.
+func (p *lpReader) label() string {
+	if p.peek(0).kind == lpName && p.peek(1).kind == lpColon {
+		p.pos += 2
+		return p.tokens[p.pos-2].text
+	}
+	return ""
+}
+
+// Consumes a signed number if there is one, where inf and infinity are numbers as well.
+func (p *lpReader) constant() (value float64, ok bool) {
+	sign, k := 1.0, 0
+	for p.peek(k).kind == lpSign {
+		sign *= p.peek(k).value
+		k++
+	}
+	t := p.peek(k)
+	switch {
+	case t.kind == lpNumber:
+		value = t.value
+	case t.kind == lpName && (strings.EqualFold(t.text, "inf") || strings.EqualFold(t.text, "infinity")):
+		value = math.Inf(1)
+	default:
+		return 0, false
+	}
+	p.pos += k + 1
+	return sign * value, true
+}
+
+/*
+Consumes a linear expression up to the next relation, colon, section keyword or the end and returns its
+terms and the sum of its constants.
+*/
+func (p *lpReader) expression() (terms []Term, constant float64, err error) {
+	for {
+		if t := p.peek(0); t.kind == lpRelation || t.kind == lpColon || t.kind == lpEnd || t.first && p.isSection() {
+			return terms, constant, nil
+		}
+		start := p.pos
+		coefficient := 1.0
+		for p.peek(0).kind == lpSign {
+			coefficient *= p.peek(0).value
+			p.pos++
+		}
+		number := p.peek(0).kind == lpNumber
+		if number {
+			coefficient *= p.peek(0).value
+			p.pos++
+		}
+		if p.peek(0).kind == lpName && !(p.peek(0).first && p.isSection()) && p.peek(1).kind != lpColon {
+			terms = append(terms, T(coefficient, p.variable(p.peek(0).text)))
+			p.pos++
+		} else if number {
+			constant += coefficient
+		} else {
+			p.pos = start
+			return nil, 0, p.errorf("expected a term")
+		}
+		// the next term has to start with a sign
+		if t := p.peek(0); t.kind != lpSign && t.kind != lpRelation && t.kind != lpColon && t.kind != lpEnd && !(t.first && p.isSection()) {
+			return nil, 0, p.errorf("expected a sign or a relation instead of %v", t.text)
+		}
+	}
+}
+
+// Whether a section keyword starts at the current position, without consuming it.
+func (p *lpReader) isSection() bool {
+	pos := p.pos
+	keyword := p.section()
+	p.pos = pos
+	return keyword != ""
+}
+
+func (p *lpReader) objective(sense string) error {
+	p.m.Sense = Maximization
+	if sense == "minimize" {
+		p.m.Sense = Minimization
+	}
+	p.label()
+	terms, constant, err := p.expression()
+	if err != nil {
+		return err
+	}
+	if constant != 0 {
+		return p.errorf("constants in the objective are not supported")
+	}
+	if t := p.peek(0); t.kind != lpEnd && !(t.first && p.isSection()) {
+		return p.errorf("unexpected %v in the objective", t.text)
+	}
+	p.m.Objective = terms
+	return nil
+}
+
+/*
+Consumes both sides of a constraint or bound: an optional constant and relation, the expression
+and an optional relation and constant. Returns the bounds on the expression they imply, NaN for
+a side that is not given.
+*/
+func (p *lpReader) sides(expression func() error) (lower float64, upper float64, err error) {
+	lower, upper = math.NaN(), math.NaN()
+	side := func(value float64, relation Relation, left bool) {
+		if left && relation != Equal {
+			relation = 1 - relation
+		}
+		switch relation {
+		case LessEqual:
+			upper = value
+		case GreaterEqual:
+			lower = value
+		case Equal:
+			lower, upper = value, value
+		}
+	}
+
+	sides := 0
+	pos := p.pos
+	if value, ok := p.constant(); ok && p.peek(0).kind == lpRelation {
+		side(value, p.peek(0).relation, true)
+		p.pos++
+		sides++
+	} else {
+		p.pos = pos
+	}
+	if err := expression(); err != nil {
+		return 0, 0, err
+	}
+	if t := p.peek(0); t.kind == lpRelation {
+		p.pos++
+		value, ok := p.constant()
+		if !ok {
+			return 0, 0, p.errorf("expected a number after %v", t.text)
+		}
+		side(value, t.relation, false)
+		sides++
+	}
+	if sides == 0 {
+		return 0, 0, p.errorf("expected a relation")
+	}
+	return lower, upper, nil
+}
+
+func (p *lpReader) constraint() error {
+	name := p.label()
+	var terms []Term
+	var constant float64
+	lower, upper, err := p.sides(func() (err error) {
+		terms, constant, err = p.expression()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(lower) {
+		lower = math.Inf(-1)
+	}
+	if math.IsNaN(upper) {
+		upper = math.Inf(1)
+	}
+	p.m.AddRange(name, lower-constant, terms, upper-constant)
+	return nil
+}
+
+func (p *lpReader) bound() error {
+	if t := p.peek(0); t.kind == lpName && strings.EqualFold(p.peek(1).text, "free") && p.peek(1).kind == lpName {
+		v := p.variable(t.text)
+		v.Lower, v.Upper = math.Inf(-1), math.Inf(1)
+		p.pos += 2
+		return nil
+	}
+	var v *Variable
+	lower, upper, err := p.sides(func() error {
+		if p.peek(0).kind != lpName {
+			return p.errorf("expected a variable")
+		}
+		v = p.variable(p.peek(0).text)
+		p.pos++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !math.IsNaN(lower) {
+		v.Lower = lower
+	}
+	if !math.IsNaN(upper) {
+		v.Upper = upper
+	}
+	return nil
+}
+
+// Consumes a variable of the generals or binaries section.
+func (p *lpReader) integer(binary bool) error {
+	t := p.peek(0)
+	if t.kind != lpName {
+		return p.errorf("expected a variable")
+	}
+	v := p.variable(t.text)
+	v.Integer = true
+	if binary {
+		v.Lower, v.Upper = 0, 1
+	}
+	p.pos++
+	return nil
+}
+
+/*
+Writes the model in CPLEX LP format. Constraints with two finite sides are written as ranges, and
+constraints without any as -inf <= ... <= +inf. Names must not start with a digit or a period,
+must not contain spaces or any of +-<>=:\*^, and must not be a keyword of the format.
+*/
+func WriteLP(w io.Writer, m *Model) error {
+	variables, constraints, err := fileNames(m, lpValidName)
+	if err != nil {
+		return err
+	}
+	names := map[*Variable]string{}
+	for i, v := range m.Variables {
+		names[v] = variables[i]
+	}
+	for _, terms := range append([][]Term{m.Objective}, constraintTerms(m)...) {
+		for _, t := range terms {
+			if t.Variable == nil || names[t.Variable] == "" {
+				return fmt.Errorf("%w: term refers to a variable that is not part of the model", ErrInvalidModel)
+			}
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	number := func(v float64) string {
+		switch {
+		case math.IsInf(v, 1):
+			return "+inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	// writes the expression, breaking lines that get longer than 80 characters
+	expression := func(line string, terms []Term) string {
+		if len(terms) == 0 {
+			return line + " 0"
+		}
+		for k, t := range terms {
+			term := ""
+			switch {
+			case t.Coefficient < 0:
+				term = "- "
+			case k > 0:
+				term = "+ "
+			}
+			if c := math.Abs(t.Coefficient); c != 1 {
+				term += number(c) + " "
+			}
+			term += names[t.Variable]
+			if len(line)+1+len(term) > 80 {
+				fmt.Fprintln(out, line)
+				line = "  "
+			}
+			line += " " + term
+		}
+		return line
+	}
+
+	if m.Sense == Maximization {
+		fmt.Fprintln(out, "Maximize")
+	} else {
+		fmt.Fprintln(out, "Minimize")
+	}
+	fmt.Fprintln(out, expression(" obj:", m.Objective))
+
+	fmt.Fprintln(out, "Subject To")
+	for i, con := range m.Constraints {
+		label := " " + constraints[i] + ":"
+		switch {
+		case len(con.Terms) == 0:
+			// a constant on the left would start a range, so this is one as well
+			fmt.Fprintln(out, label, number(con.Lower), "<= 0 <=", number(con.Upper))
+		case con.Lower == con.Upper:
+			fmt.Fprintln(out, expression(label, con.Terms), "=", number(con.Upper))
+		case math.IsInf(con.Lower, -1) && !math.IsInf(con.Upper, 1):
+			fmt.Fprintln(out, expression(label, con.Terms), "<=", number(con.Upper))
+		case math.IsInf(con.Upper, 1) && !math.IsInf(con.Lower, -1):
+			fmt.Fprintln(out, expression(label, con.Terms), ">=", number(con.Lower))
+		default:
+			fmt.Fprintln(out, expression(label+" "+number(con.Lower)+" <=", con.Terms), "<=", number(con.Upper))
+		}
+	}
+
+	fmt.Fprintln(out, "Bounds")
+	var integers []string
+	for i, v := range m.Variables {
+		name := variables[i]
+		switch {
+		case v.Lower == v.Upper:
+			fmt.Fprintf(out, " %v = %v\n", name, number(v.Lower))
+		case math.IsInf(v.Lower, -1) && math.IsInf(v.Upper, 1):
+			fmt.Fprintf(out, " %v free\n", name)
+		case v.Lower == 0 && math.IsInf(v.Upper, 1):
+		case v.Lower == 0:
+			fmt.Fprintf(out, " %v <= %v\n", name, number(v.Upper))
+		default:
+			fmt.Fprintf(out, " %v <= %v <= %v\n", number(v.Lower), name, number(v.Upper))
+		}
+		if v.Integer {
+			integers = append(integers, name)
+		}
+	}
+	if len(integers) > 0 {
+		fmt.Fprintln(out, "Generals")
+		for _, name := range integers {
+			fmt.Fprintln(out, "", name)
+		}
+	}
+	fmt.Fprintln(out, "End")
+	return out.Flush()
+}
+
+func constraintTerms(m *Model) [][]Term {
+	terms := make([][]Term, len(m.Constraints))
+	for i, con := range m.Constraints {
+		terms[i] = con.Terms
+	}
+	return terms
+}
+
+func lpValidName(name string) bool {
+	if name[0] >= '0' && name[0] <= '9' || name[0] == '.' || strings.ContainsAny(name, lpSpecial) {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) {
+			return false
+		}
+	}
+	switch strings.ToLower(name) {
+	case "maximize", "maximum", "max", "minimize", "minimum", "min", "subject", "such", "st", "st.", "s.t.",
+		"bounds", "bound", "generals", "general", "gen", "binaries", "binary", "bin", "end", "free", "inf", "infinity":
+		return false
+	}
+	return true
+}
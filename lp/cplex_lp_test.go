@@ -0,0 +1,100 @@
+package lp_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLPRoundTrip(t *testing.T) {
+	m := fileExampleModel()
+	var out bytes.Buffer
+	assert.NoError(t, lp.WriteLP(&out, m))
+
+	read, err := lp.ReadLP(&out)
+	assert.NoError(t, err)
+	assertSameModel(t, m, read)
+}
+
+func TestReadLP(t *testing.T) {
+	file := `\ max 3x + 2y s.t. x + y <= 4, x + 3y <= 6, x <= 3
+MAXIMIZE
+  profit: 3 x + 2y
+SUBJECT TO
+  c1: x + y
+      <= 4
+  x + 3 y - 1 =< 5
+  -2 <= x - y <= +inf
+bounds
+  x <= 3
+  -inf <= z <= 2e1
+  5 >= z
+  y >= -infinity
+  y >= 0
+general
+  z
+binary
+  b
+end
+`
+	m, err := lp.ReadLP(strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, lp.Maximization, m.Sense)
+	if assert.Len(t, m.Variables, 4) {
+		assert.Equal(t, []float64{0, 3}, []float64{m.Variables[0].Lower, m.Variables[0].Upper})
+		assert.Equal(t, []float64{0, math.Inf(1)}, []float64{m.Variables[1].Lower, m.Variables[1].Upper})
+		assert.Equal(t, []float64{math.Inf(-1), 5}, []float64{m.Variables[2].Lower, m.Variables[2].Upper})
+		assert.True(t, m.Variables[2].Integer)
+		assert.Equal(t, []float64{0, 1}, []float64{m.Variables[3].Lower, m.Variables[3].Upper})
+		assert.True(t, m.Variables[3].Integer)
+	}
+	if assert.Len(t, m.Constraints, 3) {
+		assert.Equal(t, "c1", m.Constraints[0].Name)
+		assert.Equal(t, []float64{math.Inf(-1), 6}, []float64{m.Constraints[1].Lower, m.Constraints[1].Upper})
+		assert.Equal(t, []float64{-2, math.Inf(1)}, []float64{m.Constraints[2].Lower, m.Constraints[2].Upper})
+	}
+
+	solution, err := m.Solve()
+	assert.NoError(t, err)
+	assert.InDelta(t, 11, solution.Objective, epsilon)
+	assert.InDelta(t, 3, solution.Values["x"], epsilon)
+	assert.InDelta(t, 1, solution.Values["y"], epsilon)
+}
+
+func TestReadLPErrors(t *testing.T) {
+	for _, file := range []string{
+		"max\n x\nst\n x <= 1\n",
+		"max\n x + 1\nend\n",
+		"max\n x y\nend\n",
+		"max\n x\nst\n x + y\nend\n",
+		"max\n x\nst\n x <= y\nend\n",
+		"max\n x\nbounds\n 2 x <= 1\nend\n",
+		"x <= 1\nend\n",
+		"max\n x\nst\n x => < 1\nend\n",
+	} {
+		_, err := lp.ReadLP(strings.NewReader(file))
+		assert.ErrorIs(t, err, lp.ErrInvalidFormat, file)
+	}
+}
+
+func TestWriteLPNames(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddNonNegativeVariable("")
+	m.AddConstraint("", nil, lp.LessEqual, 1)
+	m.SetObjective(lp.Minimization, []lp.Term{lp.T(-1, x)})
+	var out bytes.Buffer
+	assert.NoError(t, lp.WriteLP(&out, m))
+	assert.Equal(t, "Minimize\n obj: - x1\nSubject To\n c1: -inf <= 0 <= 1\nBounds\nEnd\n", out.String())
+	read, err := lp.ReadLP(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 1}, []float64{read.Constraints[0].Lower, read.Constraints[0].Upper})
+
+	for _, name := range []string{"1x", "x-y", "free", "a b"} {
+		m.Variables[0].Name = name
+		assert.ErrorIs(t, lp.WriteLP(&out, m), lp.ErrInvalidModel, name)
+	}
+}
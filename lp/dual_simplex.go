@@ -1,62 +1,93 @@
 package lp
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"math"
-	"os"
 	"slices"
 
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
 	"github.com/JonasBernard/min-cost-max-flow/util"
-	"github.com/jedib0t/go-pretty/v6/table"
 )
 
-// Works on the problem min y@b s.t. y@A = c, y >= 0
-func DualSimplex(A [][]float64, b []float64, c []float64, startbasis []int) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+/*
+Works on the problem min y@b s.t. y@A = c, y >= 0, by default with Bland's pivot rule, see WithPivotRule.
+If it is unbounded, which means that the primal
+A@x <= b is infeasible, the error is a *DualUnboundedError[float64] that certifies it.
+*/
+func DualSimplex(A [][]float64, b []float64, c []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	return DualSimplexContext(context.Background(), A, b, c, startbasis, opts...)
+}
+
+/*
+Same as DualSimplex, but stops as soon as ctx is cancelled or its deadline passes.
+In that case the current dual feasible y, its objective value y@b (an upper bound on the
+primal optimum), the current basis and the basic (in general primal infeasible) solution x
+are returned together with an error that matches util.ErrInterrupted.
+*/
+func DualSimplexContext(ctx context.Context, A [][]float64, b []float64, c []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	x, y, optimalValue, endbasis, err = DualSimplexOver(ctx, collectOptions(opts).float64Field(), A, b, c, startbasis, opts...)
+	if errors.Is(err, ErrUnbounded) {
+		optimalValue = math.Inf(-1)
+	}
+	return
+}
+
+/*
+Same as DualSimplexContext, but in the arithmetic of an arbitrary field.
+If the dual program is unbounded, the zero of the field is returned as optimal value.
+*/
+func DualSimplexOver[T any](ctx context.Context, f numbers.Field[T], A [][]T, b []T, c []T, startbasis []int, opts ...Option) (x []T, y []T, optimalValue T, endbasis []int, err error) {
+	o := collectOptions(opts)
+
 	n := len(A[0])
 	m := len(A)
 
 	basis := Basis{
 		Indices: make([]int, len(startbasis)),
-		YValues: make([]float64, m),
 	}
 	copy(basis.Indices, startbasis)
-
-	for j := 0; j < m; j++ {
-		basis.YValues[j] = 0
+	yValues := filled(m, f.Zero())
+	injectY := func(y_B []T) {
+		for k, bk := range basis.Indices {
+			yValues[bk] = y_B[k]
+		}
 	}
 
 	A_BT := util.Transpose(util.GetRows(A, basis.Indices))
-	y_B, error := SolveLinearSystem(A_BT, c)
+	y_B, error := SolveLinearSystemOver(f, A_BT, c)
 	if error != nil {
-		return nil, nil, 0, nil, error
+		return nil, nil, f.Zero(), nil, error
 	}
-	basis.injectY(y_B)
+	injectY(y_B)
 
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-
-	t.AppendHeader(table.Row{"Iter", "Basis", "Nonbasis", "y", "A_B", "x", "A_N", "z_N", "j", "w_B", "i", "gamma", "Dual objective"})
-
-	t.AppendRow(table.Row{
-		"-", basis.Indices, "", util.PrintVector(basis.YValues),
-	})
-	t.AppendSeparator()
+	rule := o.pivoting()
+	degenerate := 0
 
 	iter := 0
+	finish := func(status Status, objective T) {
+		o.observe(SolverFinished{Solver: "dual simplex", Iterations: iter, Status: status, Objective: f.Float(objective)})
+	}
+
 	for {
 		iter++
 
-		if iter > maxIterations {
-			t.AppendFooter(table.Row{"Maximum", "number", "of", "iterations", "reached"})
-			t.Render()
-			return nil, nil, 0, basis.Indices, ErrMaxIterationsReached
+		if o.exhausted(iter) {
+			finish(StatusMaxIterations, dot(f, yValues, b))
+			return nil, nil, f.Zero(), basis.Indices, ErrMaxIterationsReached
 		}
 
 		A_B := util.GetRows(A, basis.Indices)
 		b_B := util.GetValues(b, basis.Indices)
-		x, error := SolveLinearSystem(A_B, b_B)
+		x, error := SolveLinearSystemOver(f, A_B, b_B)
 		if error != nil {
-			return nil, nil, 0, nil, error
+			finish(StatusFailed, dot(f, yValues, b))
+			return nil, nil, f.Zero(), nil, error
+		}
+
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted, dot(f, yValues, b))
+			return x, yValues, dot(f, yValues, b), basis.Indices, err
 		}
 
 		N := make([]int, m-n)
@@ -70,47 +101,66 @@ func DualSimplex(A [][]float64, b []float64, c []float64, startbasis []int) (x [
 
 		b_N := util.GetValues(b, N)
 		A_N := util.GetRows(A, N)
-		z_N := util.VectorSub(b_N, util.MatMul(A_N, x))
-
-		j := util.Find(N, func(l int) bool {
-			return z_N[basis.ToNonBasisIndex(m, l)] < 0.0
-		})
-
-		if j == -1 {
-			t.AppendRow(table.Row{
-				iter,
-				fmt.Sprint(basis.Indices) + "\n" + fmt.Sprint(util.MapSlice(basis.Indices, func(t *int) int { return *t + 1 })),
-				N,
-				util.PrintVector(basis.YValues),
-				util.PrintMatrix(A_B),
-				x,
-				util.PrintMatrix(A_N),
-				util.PrintVector(z_N),
-				"", "", "", "",
-				util.DotProduct(basis.YValues, b),
-			})
-			t.AppendFooter(table.Row{"Optimal"})
-			t.Render()
-			return x, basis.YValues, util.DotProduct(basis.YValues, b), basis.Indices, nil
+		z_N := subVec(f, b_N, matVec(f, A_N, x))
+
+		event := DualSimplexIteration{Entering: -1, Leaving: -1}
+		if o.tracing() {
+			event.Iteration = iter
+			event.Basis = slices.Clone(basis.Indices)
+			event.NonBasis = N
+			event.Y = numbers.ToFloats(f, yValues)
+			event.BasisMatrix = numbers.ToFloatMatrix(f, A_B)
+			event.X = numbers.ToFloats(f, x)
+			event.NonBasisMatrix = numbers.ToFloatMatrix(f, A_N)
+			event.Slacks = numbers.ToFloats(f, z_N)
+			event.Objective = f.Float(dot(f, yValues, b))
+		}
+
+		var candidates []PivotCandidate
+		for k, l := range N {
+			if f.Sign(z_N[k]) < 0 {
+				candidates = append(candidates, PivotCandidate{Row: l, Price: -f.Float(z_N[k])})
+			}
+		}
+
+		if len(candidates) == 0 {
+			o.observe(event)
+			finish(StatusOptimal, dot(f, yValues, b))
+			return x, yValues, dot(f, yValues, b), basis.Indices, nil
 		}
 
-		jGlobal := N[j]
-		A_jT := util.GetRow(A, jGlobal)
 		A_BT := util.Transpose(A_B)
+		// the edge of the dual along which row l enters the basis is A_B^-T@a_l
+		norm := func(k int) float64 {
+			edge, err := SolveLinearSystemOver(f, A_BT, util.GetRow(A, candidates[k].Row))
+			if err != nil {
+				return math.Inf(1)
+			}
+			return f.Float(dot(f, edge, edge))
+		}
+		jGlobal := selectPivot(rule, degenerate, candidates, norm)
+		A_jT := util.GetRow(A, jGlobal)
 
-		w_B, error := SolveLinearSystem(A_BT, A_jT)
+		w_B, error := SolveLinearSystemOver(f, A_BT, A_jT)
 		if error != nil {
-			return nil, nil, 0, basis.Indices, error
+			finish(StatusFailed, dot(f, yValues, b))
+			return nil, nil, f.Zero(), basis.Indices, error
 		}
 
-		iSelect := util.FindAll(w_B, func(w float64) bool { return w > 0.0 })
+		iSelect := util.FindAll(w_B, func(w T) bool { return f.Sign(w) > 0 })
 		if len(iSelect) == 0 {
-			t.AppendFooter(table.Row{"Problem", "is", "unbounded", "in", "dual", "formulation"})
-			t.Render()
-			return nil, nil, math.Inf(-1), nil, ErrUnbounded
+			o.observe(event)
+			finish(StatusUnbounded, f.Zero())
+			// a_j = w_B@A_B with w_B <= 0, so u_j = 1, u_B = -w_B combines the rows to zero while u@b = z_j < 0
+			ray := filled(m, f.Zero())
+			ray[jGlobal] = f.One()
+			for k, w := range w_B {
+				ray[basis.ToGlobalIndex(k)] = f.Neg(w)
+			}
+			return nil, nil, f.Zero(), nil, &DualUnboundedError[T]{Y: yValues, Ray: ray}
 		}
 
-		gamma := math.Inf(1)
+		var gamma T
 		i := -1
 		for _, iCandidate := range iSelect {
 			globalICandidate := basis.ToGlobalIndex(iCandidate)
@@ -118,40 +168,56 @@ func DualSimplex(A [][]float64, b []float64, c []float64, startbasis []int) (x [
 				continue
 			}
 
-			ratio := basis.YValues[globalICandidate] / w_B[iCandidate]
-			if ratio < gamma {
+			ratio := f.Div(yValues[globalICandidate], w_B[iCandidate])
+			if i == -1 || f.Cmp(ratio, gamma) < 0 {
 				gamma = ratio
 				i = globalICandidate
 			}
 		}
 
-		t.AppendRows([]table.Row{
-			{iter,
-				fmt.Sprint(basis.Indices) + "\n" + fmt.Sprint(util.MapSlice(basis.Indices, func(t *int) int { return *t + 1 })),
-				N,
-				util.PrintVector(basis.YValues),
-				util.PrintMatrix(A_B),
-				x,
-				util.PrintMatrix(A_N),
-				util.PrintVector(z_N),
-				jGlobal,
-				util.PrintVector(w_B),
-				i,
-				gamma,
-				util.DotProduct(basis.YValues, b),
-			},
-		})
-		t.AppendSeparator()
-
-		y_B := util.GetValues(basis.YValues, basis.Indices)
-		y_B_update := util.VectorSub(y_B, util.ScalarMult(gamma, w_B))
-		basis.injectY(y_B_update)
+		event.Entering = jGlobal
+		event.Leaving = i
+		if o.tracing() {
+			event.Direction = numbers.ToFloats(f, w_B)
+			event.StepLength = f.Float(gamma)
+		}
+		o.observe(event)
+
+		if f.Sign(gamma) == 0 {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+
+		// the edges of the other non-basic rows change by their entry in the leaving position relative to the one of a_j
+		ratios := func() map[int]float64 {
+			p := basis.ToBasisIndex(i)
+			e_p := filled(n, f.Zero())
+			e_p[p] = f.One()
+			column, err := SolveLinearSystemOver(f, A_B, e_p)
+			if err != nil {
+				// A_B^T was solved with above, so only rounding errors get here, which leave the weights as they are
+				return nil
+			}
+			ratios := map[int]float64{i: 1 / f.Float(w_B[p])}
+			for _, l := range N {
+				if l != jGlobal {
+					ratios[l] = f.Float(dot(f, util.GetRow(A, l), column)) / f.Float(w_B[p])
+				}
+			}
+			return ratios
+		}
+		rule.Pivoted(jGlobal, ratios)
+
+		y_B := util.GetValues(yValues, basis.Indices)
+		y_B_update := subVec(f, y_B, scale(f, gamma, w_B))
+		injectY(y_B_update)
 
 		basis.Indices = util.RemoveValue(basis.Indices, i)
 		basis.Indices = append(basis.Indices, jGlobal)
 
 		slices.Sort(basis.Indices)
 
-		basis.YValues[jGlobal] = gamma
+		yValues[jGlobal] = gamma
 	}
 }
@@ -1,34 +1,206 @@
 package lp
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"math"
+	"slices"
 
 	"github.com/JonasBernard/min-cost-max-flow/util"
-	"github.com/jedib0t/go-pretty/v6/table"
 )
 
-func EllipsoidStep(A [][]float64, a []float64, c []float64, t table.Writer) ([][]float64, []float64) {
-	n := len(c)
+/*
+Describes a convex set by answering for any point whether it lies in the set, and if not, with an
+inequality a@y <= beta that holds for every point y of the set, but not for x. The set does not
+need to be given explicitly, for example it may have exponentially many constraints of which the
+oracle only finds a violated one.
+*/
+type SeparationOracle interface {
+	Separate(x []float64) (a []float64, beta float64, contained bool)
+}
+
+/*
+The separation oracle of an explicit system A@x <= b, which returns its most violated row relative
+to the length of the row. A zero row with a negative right hand side is violated everywhere.
+*/
+type SystemOracle struct {
+	A [][]float64
+	B []float64
+}
+
+func (s SystemOracle) Separate(x []float64) (a []float64, beta float64, contained bool) {
+	worst, violation := -1, 0.0
+	for i, row := range s.A {
+		v := util.DotProduct(row, x) - s.B[i]
+		if length := norm(row); length > 0 {
+			v /= length
+		} else if v > 0 {
+			v = math.Inf(1)
+		}
+		if v > violation {
+			worst, violation = i, v
+		}
+	}
+	if worst == -1 {
+		return nil, 0, true
+	}
+	return s.A[worst], s.B[worst], false
+}
+
+/*
+The ellipsoid method: finds a point of the convex set of the oracle within the ball of the given
+radius around center, or proves that the set does not contain a ball of radius epsilon.
+
+It keeps an ellipsoid E = {x : (x-a)@D^-1@(x-a) <= 1} that contains the part of the set within the
+start ball. As long as its center a is not in the set, the oracle's inequality cuts E, and E is
+replaced by the smallest ellipsoid containing the part of E that satisfies the inequality. Since the
+inequality is violated at a, the cut is deep: with alpha = (a@a_cut - beta) / sqrt(a_cut@D@a_cut) > 0,
+the volume shrinks by a factor of at most exp(-(1+n*alpha)^2/(2n+2)) per step. Once the volume falls
+below that of a ball of radius epsilon, ErrInfeasible is returned, which happens after at most
+2n(n+1)*ln(radius/epsilon) steps. If a cut misses E completely (alpha >= 1), ErrInfeasible is
+returned right away.
+*/
+func Ellipsoid(oracle SeparationOracle, center []float64, radius float64, epsilon float64, opts ...Option) (x []float64, err error) {
+	return EllipsoidContext(context.Background(), oracle, center, radius, epsilon, opts...)
+}
+
+// Same as Ellipsoid, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func EllipsoidContext(ctx context.Context, oracle SeparationOracle, center []float64, radius float64, epsilon float64, opts ...Option) (x []float64, err error) {
+	n := len(center)
+	if n == 0 || radius <= 0 || epsilon <= 0 || epsilon > radius {
+		return nil, ErrInvalidInput
+	}
+	o := collectOptions(opts)
 	nf := float64(n)
 
-	Ac := util.MatMul(A, c)
-	cAc := util.DotProduct(c, Ac)
-	d := util.ScalarMult(1.0/math.Sqrt(cAc), Ac)
+	iter := 0
+	finish := func(status Status) {
+		o.observe(SolverFinished{Solver: "ellipsoid", Iterations: iter, Status: status})
+	}
+
+	a := slices.Clone(center)
+	// E is kept as D = B@B^T, which stays positive semidefinite despite rounding errors
+	B := util.ScalarMatrixMult(radius, util.IdentityMatrix(n))
+	// the logarithm of the volume of E relative to the unit ball, ln sqrt(det D)
+	volume := nf * math.Log(radius)
+	minVolume := nf * math.Log(epsilon)
 
-	a_new := util.VectorSub(a, util.ScalarMult(1.0/(nf+1.0), d))
+	for {
+		iter++
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted)
+			return nil, err
+		}
+
+		cut, beta, contained := oracle.Separate(a)
+		event := EllipsoidIteration{Iteration: iter, Volume: volume}
+		if o.tracing() {
+			event.Center = slices.Clone(a)
+			event.Cut = slices.Clone(cut)
+		}
+		if contained {
+			o.observe(event)
+			finish(StatusOptimal)
+			return a, nil
+		}
+		if volume < minVolume {
+			o.observe(event)
+			finish(StatusInfeasible)
+			return nil, ErrInfeasible
+		}
+
+		if !slices.ContainsFunc(cut, func(v float64) bool { return v != 0 }) {
+			// 0 <= beta is violated, so the set is empty
+			o.observe(event)
+			finish(StatusInfeasible)
+			return nil, ErrInfeasible
+		}
+		g := util.MatMul(util.Transpose(B), cut)
+		cutDcut := util.DotProduct(g, g)
+		if cutDcut <= 0 {
+			// B has become singular, rounding errors have taken over
+			o.observe(event)
+			finish(StatusFailed)
+			return nil, ErrSingularMatrix
+		}
+		alpha := (util.DotProduct(cut, a) - beta) / math.Sqrt(cutDcut)
+		event.Depth = alpha
+		o.observe(event)
+		if alpha >= 1 {
+			finish(StatusInfeasible)
+			return nil, ErrInfeasible
+		}
+
+		g = util.ScalarMult(1/math.Sqrt(cutDcut), g)
+		// d = D@cut / sqrt(cut@D@cut)
+		d := util.MatMul(B, g)
+		a = util.VectorSub(a, util.ScalarMult((1+nf*alpha)/(nf+1), d))
+		if n == 1 {
+			// E is an interval, which the cut shortens to its remaining part
+			B = [][]float64{{B[0][0] * (1 - alpha) / 2}}
+			volume += math.Log((1 - alpha) / 2)
+			continue
+		}
+		factor := nf * nf * (1 - alpha*alpha) / (nf*nf - 1)
+		shrink := 2 * (1 + nf*alpha) / ((nf + 1) * (1 + alpha))
+		// D - shrink*d@d^T = (B - kappa*d@g^T)@(B - kappa*d@g^T)^T, as B@g = d and g@g = 1
+		kappa := 1 - math.Sqrt(1-shrink)
+		B = util.ScalarMatrixMult(math.Sqrt(factor), util.MatrixSub(B, util.ScalarMatrixMult(kappa, util.ReverseDotProduct(d, g))))
+		// det(D - shrink*d@d^T) = det(D)*(1 - shrink), as d@D^-1@d = 1
+		volume += (nf*math.Log(factor) + math.Log(1-shrink)) / 2
+	}
+}
+
+/*
+Decides with the ellipsoid method whether A@x <= b, x >= 0 has a solution, where A and b are integral
+with absolute values of at most K. Returns a point that satisfies the system up to a small
+perturbation if it does.
+
+This uses the bounds of Grötschel, Lovász and Schrijver: every vertex has coordinates of absolute
+value at most D = (sqrt(n)*K)^n by Cramer's rule and Hadamard's inequality, so the ball of radius
+sqrt(n)*D around the origin contains one if the system has a solution. To make the solution set
+full-dimensional, every row is relaxed by eta = 1 / (2(n+1) * ((n+1)*K)^(n+1)), which keeps an
+infeasible system infeasible, as its Farkas certificate can be chosen with entries of at most
+((n+1)*K)^(n+1). A feasible system then contains a ball of radius eta/(sqrt(n)*K).
+*/
+func EllipsoidFeasibility(A [][]float64, b []float64, K float64, opts ...Option) (x []float64, feasible bool, err error) {
+	return EllipsoidFeasibilityContext(context.Background(), A, b, K, opts...)
+}
+
+// Same as EllipsoidFeasibility, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func EllipsoidFeasibilityContext(ctx context.Context, A [][]float64, b []float64, K float64, opts ...Option) (x []float64, feasible bool, err error) {
+	if len(A) == 0 || len(b) != len(A) || K < 1 {
+		return nil, false, ErrInvalidInput
+	}
+	n := len(A[0])
+	nf := float64(n)
+	for _, row := range A {
+		if len(row) != n {
+			return nil, false, ErrInvalidInput
+		}
+	}
 
-	a_factor := nf * nf / (nf*nf - 1.0)
-	A_new := util.ScalarMatrixMult(a_factor, util.MatrixSub(A, util.ScalarMatrixMult(2.0/(nf+1.0), util.ReverseDotProduct(d, d))))
+	radius := math.Sqrt(nf) * math.Pow(math.Sqrt(nf)*K, nf)
+	eta := 1 / (2 * (nf + 1) * math.Pow((nf+1)*K, nf+1))
+	epsilon := eta / (math.Sqrt(nf) * K)
+	// the ball of radius epsilon around a vertex has to fit in as well
+	radius += epsilon
 
-	t.AppendRow(table.Row{
-		fmt.Sprintf("%v", Ac),
-		fmt.Sprintf("%v", cAc),
-		fmt.Sprintf("%v", d),
-		fmt.Sprintf("%v", a_new),
-		fmt.Sprintf("%v", a_factor),
-		fmt.Sprintf("%v", A_new),
-	})
+	relaxed := make([]float64, len(b), len(b)+n)
+	for i := range b {
+		relaxed[i] = b[i] + eta
+	}
+	for range n {
+		relaxed = append(relaxed, eta)
+	}
+	oracle := SystemOracle{A: slices.Concat(A, util.NegMatrix(util.IdentityMatrix(n))), B: relaxed}
 
-	return A_new, a_new
+	x, err = EllipsoidContext(ctx, oracle, make([]float64, n), radius, epsilon, opts...)
+	switch {
+	case errors.Is(err, ErrInfeasible):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+	return x, true, nil
 }
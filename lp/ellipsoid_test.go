@@ -0,0 +1,79 @@
+package lp_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// The disk of the given radius around center, separated by its tangents: infinitely many inequalities.
+type diskOracle struct {
+	center []float64
+	radius float64
+}
+
+func (d diskOracle) Separate(x []float64) (a []float64, beta float64, contained bool) {
+	v := util.VectorSub(x, d.center)
+	length := math.Sqrt(util.DotProduct(v, v))
+	if length <= d.radius {
+		return nil, 0, true
+	}
+	a = util.ScalarMult(1/length, v)
+	return a, util.DotProduct(a, d.center) + d.radius, false
+}
+
+func TestEllipsoid(t *testing.T) {
+	disk := diskOracle{center: []float64{30, -40}, radius: 0.5}
+	x, err := lp.Ellipsoid(disk, []float64{0, 0}, 100, 0.1)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, math.Hypot(x[0]-30, x[1]+40), 0.5)
+
+	// outside of the start ball
+	var status lp.Status
+	_, err = lp.Ellipsoid(disk, []float64{0, 0}, 10, 0.1, recordStatus(&status))
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, status)
+
+	// the triangle x_1 + x_2 <= 1, x >= 0.25
+	triangle := lp.SystemOracle{A: [][]float64{{1, 1}, {-1, 0}, {0, -1}}, B: []float64{1, -0.25, -0.25}}
+	x, err = lp.Ellipsoid(triangle, []float64{10, 10}, 100, 1e-3)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, x[0]+x[1], 1.0)
+	assert.GreaterOrEqual(t, x[0], 0.25)
+	assert.GreaterOrEqual(t, x[1], 0.25)
+
+	// in one dimension, the ellipsoids are intervals
+	x, err = lp.Ellipsoid(lp.SystemOracle{A: [][]float64{{1}, {-1}}, B: []float64{7.5, -7}}, []float64{0}, 10, 1e-3)
+	assert.NoError(t, err)
+	assert.InDelta(t, 7.25, x[0], 0.25)
+	_, err = lp.Ellipsoid(lp.SystemOracle{A: [][]float64{{1}, {-1}}, B: []float64{7, -8}}, []float64{0}, 10, 1e-3, recordStatus(&status))
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, status)
+
+	_, err = lp.Ellipsoid(disk, []float64{0, 0}, 1, 2)
+	assert.ErrorIs(t, err, lp.ErrInvalidInput)
+}
+
+func TestEllipsoidFeasibilityAgreesWithPhaseOne(t *testing.T) {
+	random := rand.New(rand.NewSource(45))
+	for range 30 {
+		m, n := 1+random.Intn(4), 1+random.Intn(3)
+		_, A, b := randomLP(random, m, n, 3)
+
+		_, expected, _, err := lp.PhaseOne(A, b, false)
+		assert.NoError(t, err)
+		x, feasible, err := lp.EllipsoidFeasibility(A, b, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, feasible)
+		if feasible {
+			// up to the relaxation of 1/(2(n+1)*((n+1)*K)^(n+1)) <= 1/144
+			for i, row := range A {
+				assert.LessOrEqual(t, util.DotProduct(row, x), b[i]+1.0/144)
+			}
+		}
+	}
+}
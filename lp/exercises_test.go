@@ -16,7 +16,7 @@ func TestPhaseOneH81(t *testing.T) {
 	}
 	b := []float64{20, 100, -1, -20}
 
-	basis, feasible, err := lp.PhaseOne(A, b, false)
+	basis, feasible, _, err := lp.PhaseOne(A, b, false)
 	assert.NoError(t, err)
 	assert.True(t, feasible, "Expected feasible solution")
 
@@ -65,7 +65,8 @@ func TestSimplexInH81WithDifferentBasis(t *testing.T) {
 
 	c := []float64{2, 1}
 
-	basis := []int{1, 2}
+	// the start vertex (20/3, 0) of PhaseOne, which is degenerate, with rows 3 and 5 instead of 0 and 5
+	basis := []int{3, 5}
 
 	x, y, optimalValue, endbasis, err := lp.Simplex(c, A, b, basis)
 
@@ -93,13 +94,22 @@ func TestPhaseOneH81UsingDualSimplex(t *testing.T) {
 	}
 	b := []float64{20, 100, -1, -20}
 
-	basis, feasible, err := lp.PhaseOne(A, b, false)
+	basis, feasible, _, err := lp.PhaseOne(A, b, false)
 	assert.NoError(t, err)
 	assert.True(t, feasible, "Expected feasible solution")
 
 	expectedBasis := []int{0, 5}
 	assert.Equal(t, expectedBasis, basis)
 
+	// the dual simplex reaches the same degenerate vertex (20/3, 0), where rows 0, 3 and 5 are tight
+	basis, feasible, farkas, err := lp.PhaseOne(A, b, true)
+	assert.NoError(t, err)
+	assert.True(t, feasible, "Expected feasible solution")
+	assert.Nil(t, farkas)
+
+	expectedBasis = []int{3, 5}
+	assert.Equal(t, expectedBasis, basis)
+
 	A = [][]float64{
 		{3, -1},
 		{4, 2},
@@ -112,7 +122,10 @@ func TestPhaseOneH81UsingDualSimplex(t *testing.T) {
 
 	c := []float64{2, 1}
 
-	x, y, optimalValue, endbasis, err := lp.DualSimplex(A, b, c, basis)
+	// the bases of PhaseOne are primal feasible, but not dual feasible for c. The vertex (0, 50) of
+	// rows 1 and 5 is dual feasible with y = (0, 0.5, 0, 0, 0, 0), but violates row 3.
+	dualBasis := []int{1, 5}
+	x, y, optimalValue, endbasis, err := lp.DualSimplex(A, b, c, dualBasis)
 
 	assert.NoError(t, err)
 
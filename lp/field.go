@@ -0,0 +1,115 @@
+package lp
+
+import (
+	"fmt"
+
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+)
+
+// Vector and matrix arithmetic over an arbitrary numbers.Field, the counterpart of the float64 helpers in util.
+
+func dot[T any](f numbers.Field[T], a []T, b []T) T {
+	if len(a) != len(b) {
+		panic("Vectors must be of the same length for dot product: got lengths " + fmt.Sprint(len(a)) + " and " + fmt.Sprint(len(b)))
+	}
+	sum := f.Zero()
+	for i := range a {
+		sum = f.Add(sum, f.Mul(a[i], b[i]))
+	}
+	return sum
+}
+
+func matVec[T any](f numbers.Field[T], A [][]T, v []T) []T {
+	result := make([]T, len(A))
+	for i := range A {
+		result[i] = dot(f, A[i], v)
+	}
+	return result
+}
+
+func scale[T any](f numbers.Field[T], scalar T, v []T) []T {
+	result := make([]T, len(v))
+	for i := range v {
+		result[i] = f.Mul(scalar, v[i])
+	}
+	return result
+}
+
+func addVec[T any](f numbers.Field[T], a []T, b []T) []T {
+	if len(a) != len(b) {
+		panic("Vectors must be of the same length for addition")
+	}
+	result := make([]T, len(a))
+	for i := range a {
+		result[i] = f.Add(a[i], b[i])
+	}
+	return result
+}
+
+func subVec[T any](f numbers.Field[T], a []T, b []T) []T {
+	if len(a) != len(b) {
+		panic("Vectors must be of the same length for subtraction")
+	}
+	result := make([]T, len(a))
+	for i := range a {
+		result[i] = f.Sub(a[i], b[i])
+	}
+	return result
+}
+
+func negVec[T any](f numbers.Field[T], v []T) []T {
+	result := make([]T, len(v))
+	for i := range v {
+		result[i] = f.Neg(v[i])
+	}
+	return result
+}
+
+func negMatrix[T any](f numbers.Field[T], A [][]T) [][]T {
+	result := make([][]T, len(A))
+	for i := range A {
+		result[i] = negVec(f, A[i])
+	}
+	return result
+}
+
+func filled[T any](n int, value T) []T {
+	result := make([]T, n)
+	for i := range result {
+		result[i] = value
+	}
+	return result
+}
+
+func zeroMatrix[T any](f numbers.Field[T], m int, n int) [][]T {
+	A := make([][]T, m)
+	for i := range A {
+		A[i] = filled(n, f.Zero())
+	}
+	return A
+}
+
+func identityMatrix[T any](f numbers.Field[T], n int) [][]T {
+	I := zeroMatrix(f, n, n)
+	for i := range n {
+		I[i][i] = f.One()
+	}
+	return I
+}
+
+func sum[T any](f numbers.Field[T], v []T) T {
+	total := f.Zero()
+	for _, x := range v {
+		total = f.Add(total, x)
+	}
+	return total
+}
+
+// Sums up the rows of A, that is 1@A.
+func sumRows[T any](f numbers.Field[T], n int, A [][]T) []T {
+	sum := filled(n, f.Zero())
+	for _, row := range A {
+		sum = addVec(f, sum, row)
+	}
+	return sum
+}
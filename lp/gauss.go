@@ -1,8 +1,7 @@
 package lp
 
 import (
-	"math"
-
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 
 	"errors"
@@ -11,78 +10,92 @@ import (
 var ErrInvalidInput = errors.New("invalid input: system dimensions mismatch")
 var ErrSingularMatrix = errors.New("no pivot found: singular matrix")
 
+// Gauss elimination with the maximum absolute value pivot rule, where pivots within util.DefaultTolerance of zero count as zero.
 func GaussElimination(A [][]float64, b []float64) (RU [][]float64, bU []float64, err error) {
+	return GaussEliminationOver(numbers.Float64{Tolerance: util.DefaultTolerance}, A, b)
+}
+
+// Gauss elimination with the maximum absolute value pivot rule in an arbitrary field.
+func GaussEliminationOver[T any](f numbers.Field[T], A [][]T, b []T) (RU [][]T, bU []T, err error) {
 	N := len(A)
 	// Only for square matrices
 	if N == 0 || len(A[0]) != N || len(b) != N {
 		return nil, nil, ErrInvalidInput
 	}
 
-	RU = util.MapSlice(A, func(row *[]float64) []float64 {
-		newRow := make([]float64, len(*row))
+	RU = util.MapSlice(A, func(row *[]T) []T {
+		newRow := make([]T, len(*row))
 		copy(newRow, *row)
 		return newRow
 	})
-	bU = util.MapSlice(b, func(v *float64) float64 {
+	bU = util.MapSlice(b, func(v *T) T {
 		return *v
 	})
 
 	for col := 0; col < N; col++ {
 		i_max := col
 		for row := col + 1; row < N; row++ {
-			if math.Abs(RU[row][col]) > math.Abs(RU[i_max][col]) {
+			if f.Cmp(f.Abs(RU[row][col]), f.Abs(RU[i_max][col])) > 0 {
 				i_max = row
 			}
 		}
-		if RU[i_max][col] == 0 {
+		if f.Sign(RU[i_max][col]) == 0 {
 			return nil, nil, ErrSingularMatrix
 		}
 		RU[col], RU[i_max] = RU[i_max], RU[col]
 		bU[col], bU[i_max] = bU[i_max], bU[col]
 
 		for row := col + 1; row < N; row++ {
-			ratio := RU[row][col] / RU[col][col]
+			ratio := f.Div(RU[row][col], RU[col][col])
 			for j := col + 1; j < N; j++ {
-				RU[row][j] -= ratio * RU[col][j]
+				RU[row][j] = f.Sub(RU[row][j], f.Mul(ratio, RU[col][j]))
 			}
-			bU[row] -= ratio * bU[col]
-			RU[row][col] = 0
+			bU[row] = f.Sub(bU[row], f.Mul(ratio, bU[col]))
+			RU[row][col] = f.Zero()
 		}
 	}
 	return RU, bU, nil
 }
 
 func SolveLinearSystem(A [][]float64, b []float64) (x []float64, err error) {
-	RU, b, err := GaussElimination(A, b)
+	return SolveLinearSystemOver(numbers.Float64{Tolerance: util.DefaultTolerance}, A, b)
+}
+
+func SolveLinearSystemOver[T any](f numbers.Field[T], A [][]T, b []T) (x []T, err error) {
+	RU, b, err := GaussEliminationOver(f, A, b)
 
 	if err != nil {
 		return nil, err
 	}
 
 	N := len(RU)
-	x = make([]float64, N)
+	x = make([]T, N)
 
 	for i := N - 1; i >= 0; i-- {
 		x[i] = b[i]
 		for j := i + 1; j < N; j++ {
-			x[i] -= RU[i][j] * x[j]
+			x[i] = f.Sub(x[i], f.Mul(RU[i][j], x[j]))
 		}
-		x[i] /= RU[i][i]
+		x[i] = f.Div(x[i], RU[i][i])
 	}
 	return x, nil
 }
 
 func Invert(A [][]float64) (A_inv [][]float64, err error) {
+	return InvertOver(numbers.Float64{Tolerance: util.DefaultTolerance}, A)
+}
+
+func InvertOver[T any](f numbers.Field[T], A [][]T) (A_inv [][]T, err error) {
 	N := len(A)
 	if N == 0 || len(A[0]) != N {
 		return nil, ErrInvalidInput
 	}
 
-	A_inv = make([][]float64, N)
+	A_inv = make([][]T, N)
 	for i := 0; i < N; i++ {
-		e_i := make([]float64, N)
-		e_i[i] = 1
-		x_i, err := SolveLinearSystem(A, e_i)
+		e_i := filled(N, f.Zero())
+		e_i[i] = f.One()
+		x_i, err := SolveLinearSystemOver(f, A, e_i)
 		if err != nil {
 			return nil, err
 		}
@@ -1,9 +1,13 @@
 package lp_test
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
 var epsilon = 1e-9
@@ -84,3 +88,52 @@ func TestSimpleSystem4D(t *testing.T) {
 		}
 	}
 }
+
+func TestSingularDecimalSystem(t *testing.T) {
+	// the second row is three times the first, but not exactly so in float64
+	A := [][]float64{
+		{0.1, 0.3},
+		{0.3, 0.9},
+	}
+	b := []float64{1, 3}
+
+	_, err := lp.SolveLinearSystem(A, b)
+	if !errors.Is(err, lp.ErrSingularMatrix) {
+		t.Errorf("Expected the system to be singular, got %v", err)
+	}
+
+	_, err = lp.SolveLinearSystemOver(numbers.Float64{}, A, b)
+	if err != nil {
+		t.Errorf("Expected exact comparisons to miss the singularity, got %v", err)
+	}
+}
+
+func TestLUSolveAndSolveTransposed(t *testing.T) {
+	A := [][]float64{
+		{2, 1, -1},
+		{1, -1, -1},
+		{2, 2, 1},
+	}
+	b := []float64{1, 3, 1}
+
+	lu, err := lp.FactorizeLU(A)
+	if err != nil {
+		t.Fatalf("Failed to factorize: %v", err)
+	}
+
+	x := lu.Solve(b)
+	expected, _ := lp.SolveLinearSystem(A, b)
+	for i := range expected {
+		if math.Abs(x[i]-expected[i]) > epsilon {
+			t.Errorf("Expected x[%d] = %f, got %f", i, expected[i], x[i])
+		}
+	}
+
+	x = lu.SolveTransposed(b)
+	expected, _ = lp.SolveLinearSystem(util.Transpose(A), b)
+	for i := range expected {
+		if math.Abs(x[i]-expected[i]) > epsilon {
+			t.Errorf("Expected x[%d] = %f in the transposed system, got %f", i, expected[i], x[i])
+		}
+	}
+}
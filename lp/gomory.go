@@ -0,0 +1,215 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrNoCutFound = errors.New("no violated cut found")
+
+type CutFamily int
+
+const (
+	NoCuts CutFamily = iota
+	/*
+		Gomory's fractional cuts for pure integer programs. They are only derived from tableau rows
+		where all non-basic slack variables are integral, that is where the rows of A in the basis
+		only have integral coefficients on integer variables and an integral right hand side.
+	*/
+	GomoryFractional
+	// Gomory mixed-integer cuts, which are also valid if continuous variables take part.
+	GomoryMixedInteger
+)
+
+const defaultCuttingPlaneRounds = 100
+
+// Tableau rows whose basic variable is closer to an integer than this give numerically unreliable cuts.
+const minFractionality = 1e-6
+
+/*
+Generates Gomory cuts a@x <= rhs from the simplex tableau of the vertex that basis defines in the
+system A@x <= b, one for every integer variable with a fractional value there. Every cut is violated
+by the vertex, but satisfied by all points of the system where x_j is integral for every j with integer[j].
+
+At the vertex, x = A_B^-1@b_B - A_B^-1@s_B, where s = b - A@x are the slacks, so row j of A_B^-1
+is the tableau row of x_j in terms of the non-basic slacks s_B. The cut is derived in terms of s_B
+and then expressed in x again.
+*/
+func GomoryCuts(A [][]float64, b []float64, basis []int, integer []bool, family CutFamily, opts ...Option) (cutsA [][]float64, cutsB []float64, err error) {
+	if family == NoCuts {
+		return nil, nil, nil
+	}
+	o := collectOptions(opts)
+	tol := o.tolerance
+	n := len(integer)
+
+	A_B := util.GetRows(A, basis)
+	inverse, err := InvertOver(o.float64Field(), A_B)
+	if err != nil {
+		return nil, nil, err
+	}
+	x := util.MatMul(inverse, util.GetValues(b, basis))
+
+	integralSlack := make([]bool, len(basis))
+	for k, i := range basis {
+		integralSlack[k] = hasIntegralSlack(A[i], b[i], integer, tol)
+	}
+
+	for j := range n {
+		if !integer[j] {
+			continue
+		}
+		f0 := x[j] - math.Floor(x[j])
+		if f0 < minFractionality || f0 > 1-minFractionality {
+			continue
+		}
+
+		// the cut gamma@s_B >= beta
+		gamma := make([]float64, len(basis))
+		beta := 1.0
+		usable := true
+		for k, alpha := range inverse[j] {
+			if tol.IsZero(alpha) {
+				continue
+			}
+			fk := alpha - math.Floor(alpha)
+			switch {
+			case family == GomoryFractional && !integralSlack[k]:
+				usable = false
+			case family == GomoryFractional:
+				gamma[k] = fk
+			case integralSlack[k] && fk <= f0:
+				gamma[k] = fk / f0
+			case integralSlack[k]:
+				gamma[k] = (1 - fk) / (1 - f0)
+			case alpha > 0:
+				gamma[k] = alpha / f0
+			default:
+				gamma[k] = -alpha / (1 - f0)
+			}
+		}
+		if !usable {
+			continue
+		}
+		if family == GomoryFractional {
+			beta = f0
+		}
+
+		// substitute s_B = b_B - A_B@x
+		row := make([]float64, n)
+		rhs := -beta
+		for k, i := range basis {
+			if gamma[k] == 0 {
+				continue
+			}
+			row = util.VectorAdd(row, util.ScalarMult(gamma[k], A[i]))
+			rhs += gamma[k] * b[i]
+		}
+		cutsA = append(cutsA, row)
+		cutsB = append(cutsB, rhs)
+	}
+	return cutsA, cutsB, nil
+}
+
+/*
+Whether the slack b_i - a@x is integral for every x that is integral on the integer variables.
+Like branchingVariable, integrality is tested with the absolute tolerance only.
+*/
+func hasIntegralSlack(a []float64, b float64, integer []bool, tol util.Tolerance) bool {
+	if !tol.IsZero(b - math.Round(b)) {
+		return false
+	}
+	for j, v := range a {
+		if integer[j] && !tol.IsZero(v-math.Round(v)) || !integer[j] && !tol.IsZero(v) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Pure cutting-plane method for the mixed integer program max c@x s.t. A@x <= b, x >= 0, x_j integral
+for all j with integer[j]. The linear relaxation is solved once, then rounds of Gomory cuts are added
+and the relaxation is re-optimized with DualSimplex from the previous basis until its solution is integral.
+
+Uses Gomory mixed-integer cuts and at most 100 rounds unless configured otherwise by WithCuts.
+If the rounds are exhausted or no cut can be derived, the result only carries the bound of the
+last relaxation and the error is ErrMaxIterationsReached or ErrNoCutFound respectively.
+*/
+func CuttingPlane(c []float64, A [][]float64, b []float64, integer []bool, opts ...Option) (result MIPResult, err error) {
+	return CuttingPlaneContext(context.Background(), c, A, b, integer, opts...)
+}
+
+// Same as CuttingPlane, but stops once ctx is done, see BranchAndBoundContext.
+func CuttingPlaneContext(ctx context.Context, c []float64, A [][]float64, b []float64, integer []bool, opts ...Option) (result MIPResult, err error) {
+	o := collectOptions(opts)
+	if len(A) == 0 || len(A[0]) != len(c) || len(b) != len(A) || len(integer) != len(c) {
+		return MIPResult{}, ErrInvalidInput
+	}
+	family, rounds := o.cutFamily, o.cutRounds
+	if family == NoCuts {
+		family, rounds = GomoryMixedInteger, defaultCuttingPlaneRounds
+	}
+
+	s := bnbSolver{ctx: ctx, o: o, c: c, A: A, b: b, integer: integer}
+	result = MIPResult{Objective: math.Inf(-1), Bound: math.Inf(1), Gap: math.Inf(1)}
+	finish := func(status Status, round int) {
+		result.Status = status
+		o.observe(SolverFinished{Solver: "cutting plane", Iterations: round, Status: status, Objective: result.Objective})
+	}
+
+	var node bnbNode
+	for round := 0; ; round++ {
+		A_node, b_node := s.system(node)
+		x, value, basis, feasible, err := s.relax(A_node, b_node, node.basis)
+		if errors.Is(err, util.ErrInterrupted) {
+			finish(StatusInterrupted, round)
+			return result, err
+		}
+		if err != nil {
+			finish(StatusFailed, round)
+			return result, err
+		}
+		if !feasible {
+			finish(StatusInfeasible, round)
+			return result, ErrInfeasible
+		}
+		result.Bound = value
+		result.Nodes = round + 1
+
+		if s.branchingVariable(x) == -1 {
+			for j := range x {
+				if integer[j] {
+					x[j] = math.Round(x[j])
+				}
+			}
+			result.X = x
+			result.Objective = util.DotProduct(c, x)
+			result.Gap = 0
+			finish(StatusOptimal, round)
+			return result, nil
+		}
+
+		if round >= rounds {
+			finish(StatusMaxIterations, round)
+			return result, ErrMaxIterationsReached
+		}
+		cutsA, cutsB, err := GomoryCuts(A_node, b_node, basis, integer, family, WithTolerance(o.tolerance))
+		if err != nil {
+			finish(StatusFailed, round)
+			return result, err
+		}
+		if len(cutsA) == 0 {
+			finish(StatusFailed, round)
+			return result, ErrNoCutFound
+		}
+		node = bnbNode{
+			rows:  append(node.rows, cutsA...),
+			rhs:   append(node.rhs, cutsB...),
+			basis: basis,
+		}
+	}
+}
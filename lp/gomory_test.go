@@ -0,0 +1,136 @@
+package lp_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// The optimal vertex of max c@x s.t. A@x <= b, x >= 0 as basis of [A; -I]@x <= [b; 0].
+func optimalVertex(t *testing.T, c []float64, A [][]float64, b []float64) (system [][]float64, rhs []float64, x []float64, basis []int) {
+	basis, feasible, _, err := lp.PhaseOne(A, b, false)
+	assert.NoError(t, err)
+	assert.True(t, feasible)
+	system = slices.Concat(A, util.NegMatrix(util.IdentityMatrix(len(c))))
+	rhs = slices.Concat(b, make([]float64, len(c)))
+	x, _, _, basis, err = lp.Simplex(c, system, rhs, basis)
+	assert.NoError(t, err)
+	return system, rhs, x, basis
+}
+
+func TestGomoryCuts(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+	}
+	b := []float64{24, 6}
+	c := []float64{5, 4}
+
+	// the relaxation's optimum is (3, 1.5)
+	system, rhs, x, basis := optimalVertex(t, c, A, b)
+	assert.InDeltaSlice(t, []float64{3, 1.5}, x, epsilon)
+
+	for _, family := range []lp.CutFamily{lp.GomoryFractional, lp.GomoryMixedInteger} {
+		cutsA, cutsB, err := lp.GomoryCuts(system, rhs, basis, []bool{true, true}, family)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, cutsA)
+		for k, cut := range cutsA {
+			assert.Greater(t, util.DotProduct(cut, x), cutsB[k]+epsilon, "the vertex must violate the cut")
+			for x1 := range 5 {
+				for x2 := range 4 {
+					point := []float64{float64(x1), float64(x2)}
+					if util.DotProduct(A[0], point) <= b[0] && util.DotProduct(A[1], point) <= b[1] {
+						assert.LessOrEqual(t, util.DotProduct(cut, point), cutsB[k]+epsilon, "the cut must not remove %v", point)
+					}
+				}
+			}
+		}
+	}
+
+	// with x_1 continuous only x_2 = 1.5 is cut off, and only by the mixed-integer cut
+	integer := []bool{false, true}
+	cutsA, _, err := lp.GomoryCuts(system, rhs, basis, integer, lp.GomoryFractional)
+	assert.NoError(t, err)
+	assert.Empty(t, cutsA)
+	cutsA, cutsB, err := lp.GomoryCuts(system, rhs, basis, integer, lp.GomoryMixedInteger)
+	assert.NoError(t, err)
+	assert.Len(t, cutsA, 1)
+	assert.Greater(t, util.DotProduct(cutsA[0], x), cutsB[0]+epsilon)
+	for x2 := range 4 {
+		// the feasible x_1 form the interval [0, min((24 - 4x_2)/6, 6 - 2x_2)], the cut is linear so checking its ends suffices
+		y := float64(x2)
+		for _, x1 := range []float64{0, min((24-4*y)/6, 6-2*y)} {
+			assert.LessOrEqual(t, util.DotProduct(cutsA[0], []float64{x1, y}), cutsB[0]+epsilon)
+		}
+	}
+
+	// the slack of x <= 1e10 + 0.5 is within the relative tolerance of an integer, but not integral
+	c, A, b = []float64{1}, [][]float64{{1}}, []float64{1e10 + 0.5}
+	system, rhs, x, basis = optimalVertex(t, c, A, b)
+	for _, family := range []lp.CutFamily{lp.GomoryFractional, lp.GomoryMixedInteger} {
+		cutsA, cutsB, err := lp.GomoryCuts(system, rhs, basis, []bool{true}, family)
+		assert.NoError(t, err)
+		for k, cut := range cutsA {
+			assert.Greater(t, util.DotProduct(cut, x), cutsB[k], "the vertex must violate the cut")
+			assert.LessOrEqual(t, util.DotProduct(cut, []float64{1e10}), cutsB[k], "the cut must not remove 1e10")
+		}
+	}
+}
+
+func TestCuttingPlane(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+	}
+	b := []float64{24, 6}
+	c := []float64{5, 4}
+
+	for _, family := range []lp.CutFamily{lp.GomoryFractional, lp.GomoryMixedInteger} {
+		result, err := lp.CuttingPlane(c, A, b, []bool{true, true}, lp.WithCuts(family, 50))
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{4, 0}, result.X)
+		assert.InDelta(t, 20, result.Objective, epsilon)
+		assert.Equal(t, lp.StatusOptimal, result.Status)
+	}
+
+	// x = 1e10 + 0.5 is within the relative tolerance of an integer, but not integral
+	for _, family := range []lp.CutFamily{lp.GomoryFractional, lp.GomoryMixedInteger} {
+		result, err := lp.CuttingPlane([]float64{1}, [][]float64{{2}}, []float64{2e10 + 1}, []bool{true}, lp.WithCuts(family, 10))
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1e10}, result.X)
+		assert.Equal(t, 1e10, result.Objective)
+	}
+
+	result, err := lp.CuttingPlane(c, A, b, []bool{true, true}, lp.WithCuts(lp.GomoryMixedInteger, 0))
+	assert.ErrorIs(t, err, lp.ErrMaxIterationsReached)
+	assert.Nil(t, result.X)
+	assert.InDelta(t, 21, result.Bound, epsilon)
+
+	// 1 <= x <= 0
+	result, err = lp.CuttingPlane([]float64{1}, [][]float64{{1}, {-1}}, []float64{0, -1}, []bool{true})
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, result.Status)
+}
+
+func TestBranchAndBoundWithCuts(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+	}
+	b := []float64{24, 6}
+	c := []float64{5, 4}
+	integer := []bool{true, true}
+
+	plain, err := lp.BranchAndBound(c, A, b, integer)
+	assert.NoError(t, err)
+	for _, family := range []lp.CutFamily{lp.GomoryFractional, lp.GomoryMixedInteger} {
+		result, err := lp.BranchAndBound(c, A, b, integer, lp.WithCuts(family, 3))
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{4, 0}, result.X)
+		assert.InDelta(t, 20, result.Objective, epsilon)
+		assert.LessOrEqual(t, result.Nodes, plain.Nodes)
+	}
+}
@@ -0,0 +1,24 @@
+package lp_test
+
+import "math/rand"
+
+// A random program max c@x s.t. A@x <= b with m rows and n columns and integer entries in [-K, K].
+func randomLP(random *rand.Rand, m, n, K int) (c []float64, A [][]float64, b []float64) {
+	entry := func() float64 {
+		return float64(random.Intn(2*K+1) - K)
+	}
+	A = make([][]float64, m)
+	b = make([]float64, m)
+	for i := range m {
+		A[i] = make([]float64, n)
+		for j := range n {
+			A[i][j] = entry()
+		}
+		b[i] = entry()
+	}
+	c = make([]float64, n)
+	for j := range n {
+		c[j] = entry()
+	}
+	return c, A, b
+}
@@ -0,0 +1,102 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrFeasible = errors.New("the system is feasible")
+
+/*
+Finds an irreducible infeasible subsystem (IIS) of A@x <= b, x >= 0: the indices of rows of A that
+are infeasible together with x >= 0, while dropping any one of them makes the rest feasible.
+The non-negativity constraints are always part of the system. If the system is feasible, ErrFeasible is returned.
+
+farkas is a certificate of infeasibility of the subsystem as returned by PhaseOne, given for all rows
+of A with zeros outside of rows. A system can have many IISs, this returns one of them.
+
+The rows involved in a Farkas certificate already are an infeasible subsystem, so the search starts
+with the support of the certificate of PhaseOne and shrinks it as long as the certificate of the
+smaller subsystem has a smaller support (certificate support shrinking). Then each remaining row
+is dropped in turn and kept out whenever the rest is still infeasible (deletion filter), which
+leaves an IIS.
+*/
+func IIS(A [][]float64, b []float64, opts ...Option) (rows []int, farkas []float64, err error) {
+	return IISContext(context.Background(), A, b, opts...)
+}
+
+// Same as IIS, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func IISContext(ctx context.Context, A [][]float64, b []float64, opts ...Option) (rows []int, farkas []float64, err error) {
+	if len(A) == 0 || len(b) != len(A) {
+		return nil, nil, ErrInvalidInput
+	}
+	tol := collectOptions(opts).tolerance
+
+	// whether the rows are infeasible together, and the certificate for them in their order
+	infeasible := func(rows []int) (bool, []float64, error) {
+		if len(rows) == 0 {
+			return false, nil, nil
+		}
+		_, feasible, y, err := PhaseOneContext(ctx, util.GetRows(A, rows), util.GetValues(b, rows), false, opts...)
+		return !feasible, y, err
+	}
+	support := func(rows []int, y []float64) []int {
+		var s []int
+		for k, i := range rows {
+			if tol.Sign(y[k]) > 0 {
+				s = append(s, i)
+			}
+		}
+		return s
+	}
+
+	rows = make([]int, len(A))
+	for i := range rows {
+		rows[i] = i
+	}
+	isInfeasible, y, err := infeasible(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isInfeasible {
+		return nil, nil, ErrFeasible
+	}
+
+	for {
+		smaller := support(rows, y)
+		if len(smaller) >= len(rows) {
+			break
+		}
+		isInfeasible, smallerY, err := infeasible(smaller)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isInfeasible {
+			// numerically doubtful certificate, leave the rest to the deletion filter
+			break
+		}
+		rows, y = smaller, smallerY
+	}
+
+	for k := 0; k < len(rows); {
+		rest := slices.Delete(slices.Clone(rows), k, k+1)
+		isInfeasible, restY, err := infeasible(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isInfeasible {
+			rows, y = rest, restY
+		} else {
+			k++
+		}
+	}
+
+	farkas = make([]float64, len(A))
+	for k, i := range rows {
+		farkas[i] = y[k]
+	}
+	return rows, farkas, nil
+}
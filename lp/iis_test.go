@@ -0,0 +1,101 @@
+package lp_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIIS(t *testing.T) {
+	A := [][]float64{
+		{1, 0},   // x_1 <= 4
+		{1, 1},   // x_1 + x_2 <= 10
+		{0, 1},   // x_2 <= 3
+		{-1, 0},  // x_1 >= 5
+		{-1, -1}, // x_1 + x_2 >= 1
+	}
+	b := []float64{4, 10, 3, -5, -1}
+
+	rows, farkas, err := lp.IIS(A, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 3}, rows)
+	assertFarkas(t, A, b, farkas)
+	for _, i := range []int{1, 2, 4} {
+		assert.Equal(t, 0.0, farkas[i])
+	}
+
+	_, _, err = lp.IIS(A[:3], b[:3])
+	assert.ErrorIs(t, err, lp.ErrFeasible)
+}
+
+func TestIISIsIrreducible(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	found := 0
+	for range 40 {
+		m, n := 4+random.Intn(5), 2+random.Intn(3)
+		A := make([][]float64, m)
+		b := make([]float64, m)
+		for i := range m {
+			A[i] = make([]float64, n)
+			for j := range n {
+				A[i][j] = float64(random.Intn(11) - 5)
+			}
+			b[i] = float64(random.Intn(21) - 12)
+		}
+
+		rows, farkas, err := lp.IIS(A, b)
+		if err != nil {
+			assert.ErrorIs(t, err, lp.ErrFeasible)
+			continue
+		}
+		found++
+		assertFarkas(t, A, b, farkas)
+
+		_, feasible, _, err := lp.PhaseOne(util.GetRows(A, rows), util.GetValues(b, rows), false)
+		assert.NoError(t, err)
+		assert.False(t, feasible)
+		for k := range rows {
+			rest := append(append([]int{}, rows[:k]...), rows[k+1:]...)
+			if len(rest) == 0 {
+				continue
+			}
+			_, feasible, _, err := lp.PhaseOne(util.GetRows(A, rest), util.GetValues(b, rest), false)
+			assert.NoError(t, err)
+			assert.True(t, feasible, "dropping row %v of %v must make the system feasible", rows[k], rows)
+		}
+	}
+	assert.Positive(t, found)
+}
+
+func TestModelIIS(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddVariable("x", 0, 4)
+	y := m.AddNonNegativeVariable("y")
+	m.AddConstraint("capacity", []lp.Term{lp.T(1, x), lp.T(1, y)}, lp.LessEqual, 10)
+	demand := m.AddConstraint("demand", []lp.Term{lp.T(1, x), lp.T(-1, y)}, lp.GreaterEqual, 5)
+	m.SetObjective(lp.Maximization, []lp.Term{lp.T(1, x)})
+
+	_, err := m.Solve()
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+
+	constraints, variables, err := m.IIS()
+	assert.NoError(t, err)
+	assert.Equal(t, []*lp.Constraint{demand}, constraints)
+	assert.Equal(t, []*lp.Variable{x}, variables)
+
+	z := m.AddVariable("z", 2, 1)
+	constraints, variables, err = m.IIS()
+	assert.NoError(t, err)
+	assert.Empty(t, constraints)
+	assert.Equal(t, []*lp.Variable{z}, variables)
+
+	feasible := lp.NewModel()
+	v := feasible.AddVariable("v", 0, math.Inf(1))
+	feasible.AddConstraint("c", []lp.Term{lp.T(1, v)}, lp.LessEqual, 1)
+	_, _, err = feasible.IIS()
+	assert.ErrorIs(t, err, lp.ErrFeasible)
+}
@@ -0,0 +1,327 @@
+package lp
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+const (
+	// relative residuals and duality gap at which InteriorPoint stops
+	interiorPointAccuracy = 1e-9
+	// how far towards the boundary of the positive orthant a step goes at most
+	interiorPointStepFactor = 0.99
+	// iterates beyond this size count as diverging
+	interiorPointDivergence = 1e12
+)
+
+/*
+Primal-dual interior point method for max c@x s.t. A@x <= b, x >= 0, following Mehrotra's
+predictor-corrector scheme. Instead of walking along the vertices, it follows the central path
+through the interior, so the number of iterations hardly grows with the size of the program,
+while every iteration solves the normal equations by a Cholesky factorization of an m x m matrix.
+
+With slacks s, it solves min -c@x s.t. A@x + s = b, x, s >= 0 together with its dual. Every
+iteration first computes the affine scaling (predictor) direction, takes from how much it would
+reduce the duality gap the centering parameter and then solves once more for the corrected
+direction with the same factorization. It stops once the primal and dual residuals and the
+duality gap are small relative to the data.
+
+x is optimal up to that accuracy, but lies in the relative interior of the optimal face rather
+than on a vertex, use Crossover to get an optimal vertex and basis. y is the dual solution for the
+rows of A. If the iterates diverge or stall, which happens for infeasible (ErrInfeasible) and
+unbounded (ErrUnbounded) programs, but also for badly scaled ones with an optimum, the program is
+solved once more by the simplex method as Solver does, which then returns an optimal vertex.
+*/
+func InteriorPoint(c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, y []float64, optimalValue float64, err error) {
+	return InteriorPointContext(context.Background(), c, A, b, opts...)
+}
+
+// Same as InteriorPoint, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func InteriorPointContext(ctx context.Context, c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, y []float64, optimalValue float64, err error) {
+	if len(A) == 0 || len(b) != len(A) {
+		return nil, nil, 0, ErrInvalidInput
+	}
+	for _, row := range A {
+		if len(row) != len(c) {
+			return nil, nil, 0, ErrInvalidInput
+		}
+	}
+	o := collectOptions(opts)
+	m, n := len(A), len(c)
+	N := n + m
+
+	iter := 0
+	finish := func(status Status) {
+		o.observe(SolverFinished{Solver: "interior point", Iterations: iter, Status: status, Objective: optimalValue})
+	}
+
+	// min q@z s.t. K@z = b, z >= 0 with z = (x, s), K = [A | I] and q = (-c, 0)
+	K := util.ConcatColumns(A, util.IdentityMatrix(m))
+	KT := util.Transpose(K)
+	q := slices.Concat(util.Neg(c), make([]float64, m))
+
+	// K@diag(d)@K^T = A@diag(d_x)@A^T + diag(d_s)
+	normal := func(d []float64) (*Cholesky, error) {
+		M := make([][]float64, m)
+		for i := range m {
+			M[i] = make([]float64, m)
+			for k := 0; k <= i; k++ {
+				for j := range n {
+					M[i][k] += A[i][j] * d[j] * A[k][j]
+				}
+			}
+			M[i][i] += d[n+i]
+		}
+		return factorizeCholesky(M, true)
+	}
+
+	z, lambda, w, err := interiorPointStart(K, KT, q, b, normal)
+	if err != nil {
+		finish(StatusFailed)
+		return nil, nil, 0, err
+	}
+
+	for {
+		iter++
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted)
+			return nil, nil, 0, err
+		}
+
+		r_b := util.VectorSub(util.MatMul(K, z), b)
+		r_c := util.VectorSub(util.VectorAdd(util.MatMul(KT, lambda), w), q)
+		mu := util.DotProduct(z, w) / float64(N)
+		primal, dual := util.DotProduct(q, z), util.DotProduct(b, lambda)
+
+		event := InteriorPointIteration{Iteration: iter, Objective: -primal, Mu: mu}
+		if o.tracing() {
+			event.PrimalResidual = norm(r_b)
+			event.DualResidual = norm(r_c)
+		}
+
+		if norm(r_b) <= interiorPointAccuracy*(1+norm(b)) &&
+			norm(r_c) <= interiorPointAccuracy*(1+norm(q)) &&
+			math.Abs(primal-dual) <= interiorPointAccuracy*(1+math.Abs(primal)) {
+			o.observe(event)
+			break
+		}
+		if o.exhausted(iter) {
+			o.observe(event)
+			finish(StatusMaxIterations)
+			return nil, nil, 0, ErrMaxIterationsReached
+		}
+		/*
+			For infeasible and unbounded programs, either the iterates grow without bound or the
+			complementarity vanishes while the residuals stay.
+		*/
+		diverged := slices.ContainsFunc(slices.Concat(z, lambda), func(v float64) bool { return math.Abs(v) > interiorPointDivergence })
+		stalled := mu <= interiorPointAccuracy*interiorPointAccuracy*(1+math.Abs(primal))
+		if diverged || stalled {
+			// this may also be a numerical failure on a program with an optimum, so only the simplex method decides
+			o.observe(event)
+			s, err := NewSolver(c, A, b, opts...)
+			if err == nil {
+				x, optimalValue, err = s.SolveContext(ctx)
+			}
+			if err != nil {
+				finish(statusOf(err))
+				return nil, nil, 0, err
+			}
+			finish(StatusOptimal)
+			return x, s.basis.YValues[:m], optimalValue, nil
+		}
+
+		d := make([]float64, N)
+		for j := range N {
+			d[j] = z[j] / w[j]
+		}
+		cholesky, err := normal(d)
+		if err != nil {
+			o.observe(event)
+			finish(StatusFailed)
+			return nil, nil, 0, err
+		}
+
+		/*
+			Newton step for K@dz = -r_b, K^T@dl + dw = -r_c, W@dz + Z@dw = -r_zw, eliminated to
+			K@D@K^T@dl = -r_b - K@D@r_c + K@(r_zw/w) with D = Z/W.
+		*/
+		direction := func(r_zw []float64) (dz []float64, dl []float64, dw []float64) {
+			v := make([]float64, N)
+			for j := range N {
+				v[j] = r_zw[j]/w[j] - d[j]*r_c[j]
+			}
+			dl = cholesky.Solve(util.VectorSub(util.MatMul(K, v), r_b))
+			dw = util.VectorSub(util.Neg(r_c), util.MatMul(KT, dl))
+			dz = make([]float64, N)
+			for j := range N {
+				dz[j] = (-r_zw[j] - z[j]*dw[j]) / w[j]
+			}
+			return dz, dl, dw
+		}
+
+		// predictor: the affine scaling direction towards zero duality gap
+		r_zw := make([]float64, N)
+		for j := range N {
+			r_zw[j] = z[j] * w[j]
+		}
+		dzAffine, _, dwAffine := direction(r_zw)
+		alphaPrimal, alphaDual := min(1, maxStep(z, dzAffine)), min(1, maxStep(w, dwAffine))
+		muAffine := util.DotProduct(
+			util.VectorAdd(z, util.ScalarMult(alphaPrimal, dzAffine)),
+			util.VectorAdd(w, util.ScalarMult(alphaDual, dwAffine)),
+		) / float64(N)
+		sigma := math.Pow(muAffine/mu, 3)
+
+		// corrector: back towards the central path, compensating the second order term of the predictor
+		for j := range N {
+			r_zw[j] += dzAffine[j]*dwAffine[j] - sigma*mu
+		}
+		dz, dl, dw := direction(r_zw)
+		alphaPrimal = min(1, interiorPointStepFactor*maxStep(z, dz))
+		alphaDual = min(1, interiorPointStepFactor*maxStep(w, dw))
+
+		if o.tracing() {
+			event.Sigma = sigma
+			event.StepPrimal = alphaPrimal
+			event.StepDual = alphaDual
+		}
+		o.observe(event)
+
+		z = util.VectorAdd(z, util.ScalarMult(alphaPrimal, dz))
+		lambda = util.VectorAdd(lambda, util.ScalarMult(alphaDual, dl))
+		w = util.VectorAdd(w, util.ScalarMult(alphaDual, dw))
+	}
+
+	x = z[:n]
+	// the dual of the equality rows is -y
+	y = util.Neg(lambda)
+	optimalValue = util.DotProduct(c, x)
+	finish(StatusOptimal)
+	return x, y, optimalValue, nil
+}
+
+/*
+Mehrotra's starting point: the least squares solutions of K@z = b and K^T@l + w = q,
+shifted into the positive orthant and then towards each other's size.
+*/
+func interiorPointStart(K [][]float64, KT [][]float64, q []float64, b []float64, normal func(d []float64) (*Cholesky, error)) (z []float64, lambda []float64, w []float64, err error) {
+	cholesky, err := normal(util.Ones(len(q)))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	z = util.MatMul(KT, cholesky.Solve(b))
+	lambda = cholesky.Solve(util.MatMul(K, q))
+	w = util.VectorSub(q, util.MatMul(KT, lambda))
+
+	shift := func(v []float64) []float64 {
+		delta := max(-1.5*slices.Min(v), 0)
+		shifted := make([]float64, len(v))
+		for j := range v {
+			shifted[j] = v[j] + delta
+		}
+		return shifted
+	}
+	z, w = shift(z), shift(w)
+
+	product, sumZ, sumW := util.DotProduct(z, w), 0.0, 0.0
+	for j := range z {
+		sumZ += z[j]
+		sumW += w[j]
+	}
+	// z and w may both be zero, when b and q are
+	deltaZ, deltaW := 1.0, 1.0
+	if product > 0 {
+		deltaZ, deltaW = 0.5*product/sumW, 0.5*product/sumZ
+	}
+	for j := range z {
+		z[j] += deltaZ
+		w[j] += deltaW
+	}
+	return z, lambda, w, nil
+}
+
+// The largest step t for which v + t*dv >= 0, +Inf if dv >= 0.
+func maxStep(v []float64, dv []float64) float64 {
+	step := math.Inf(1)
+	for j := range v {
+		if dv[j] < 0 {
+			step = min(step, -v[j]/dv[j])
+		}
+	}
+	return step
+}
+
+func norm(v []float64) float64 {
+	return math.Sqrt(util.DotProduct(v, v))
+}
+
+/*
+Moves from an optimal, but not necessarily basic solution x of max c@x s.t. A@x <= b, x >= 0, as
+InteriorPoint returns it, to an optimal vertex, and returns it with its dual solution y for the rows
+of A and its basis. Like PhaseOne, the basis refers to the rows of [A; -I].
+
+The start basis consists of the rows of [A; -I] that are closest to tight at x, skipping those
+that depend on the ones already chosen. Near the optimum, that basis is usually optimal or a few
+pivots of Simplex or DualSimplex away from it. Otherwise the program is solved from scratch.
+*/
+func Crossover(c []float64, A [][]float64, b []float64, x []float64, opts ...Option) (vertex []float64, y []float64, optimalValue float64, basis []int, err error) {
+	return CrossoverContext(context.Background(), c, A, b, x, opts...)
+}
+
+// Same as Crossover, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func CrossoverContext(ctx context.Context, c []float64, A [][]float64, b []float64, x []float64, opts ...Option) (vertex []float64, y []float64, optimalValue float64, basis []int, err error) {
+	if len(x) != len(c) {
+		return nil, nil, 0, nil, ErrInvalidInput
+	}
+	s, err := NewSolver(c, A, b, opts...)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+	tol := collectOptions(opts).tolerance
+	A_full, b_full := s.system()
+
+	rows := make([]int, len(A_full))
+	slacks := make([]float64, len(A_full))
+	for i, row := range A_full {
+		rows[i] = i
+		slacks[i] = b_full[i] - util.DotProduct(row, x)
+	}
+	slices.SortStableFunc(rows, func(i int, l int) int { return tol.Compare(slacks[i], slacks[l]) })
+
+	// the chosen rows in echelon form: a pivot column, at which the row is one and the later rows are zero
+	var pivots []int
+	var echelon [][]float64
+	for _, i := range rows {
+		if len(pivots) == len(c) {
+			break
+		}
+		v := slices.Clone(A_full[i])
+		for k, u := range echelon {
+			v = util.VectorSub(v, util.ScalarMult(v[pivots[k]], u))
+		}
+		p := 0
+		for j := range v {
+			if math.Abs(v[j]) > math.Abs(v[p]) {
+				p = j
+			}
+		}
+		if math.Abs(v[p]) <= 1e-9*norm(A_full[i]) {
+			continue
+		}
+		pivots = append(pivots, p)
+		echelon = append(echelon, util.ScalarMult(1/v[p], v))
+		basis = append(basis, i)
+	}
+	slices.Sort(basis)
+	s.basis = Basis{Indices: basis}
+
+	vertex, optimalValue, err = s.SolveContext(ctx)
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+	return vertex, s.basis.YValues[:len(A)], optimalValue, s.basis.Indices, nil
+}
@@ -0,0 +1,95 @@
+package lp_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCholesky(t *testing.T) {
+	A := [][]float64{
+		{4, 12, -16},
+		{12, 37, -43},
+		{-16, -43, 98},
+	}
+	cholesky, err := lp.FactorizeCholesky(A)
+	assert.NoError(t, err)
+	x := cholesky.Solve([]float64{1, 2, 3})
+	assert.InDeltaSlice(t, []float64{1, 2, 3}, util.MatMul(A, x), epsilon)
+
+	_, err = lp.FactorizeCholesky([][]float64{{1, 2}, {2, 1}})
+	assert.ErrorIs(t, err, lp.ErrSingularMatrix)
+}
+
+func TestInteriorPoint(t *testing.T) {
+	c := []float64{3, 2}
+	A := [][]float64{{1, 1}, {1, 0}, {0, 1}}
+	b := []float64{4, 3, 3}
+	x, y, optimalValue, err := lp.InteriorPoint(c, A, b)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{3, 1}, x, 1e-6)
+	assert.InDelta(t, 11, optimalValue, 1e-6)
+	assertOptimalPair(t, c, A, b, x, y)
+
+	var status lp.Status
+	_, _, _, err = lp.InteriorPoint(c, A, b, lp.WithMaxIterations(1), recordStatus(&status))
+	assert.ErrorIs(t, err, lp.ErrMaxIterationsReached)
+	assert.Equal(t, lp.StatusMaxIterations, status)
+
+	// x_1 + x_2 <= 1 and x_1 + x_2 >= 2
+	_, _, _, err = lp.InteriorPoint([]float64{1, 1}, [][]float64{{1, 1}, {-1, -1}}, []float64{1, -2}, recordStatus(&status))
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	assert.Equal(t, lp.StatusInfeasible, status)
+
+	_, _, _, err = lp.InteriorPoint([]float64{1, 1}, [][]float64{{1, -1}}, []float64{1})
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+
+	// the iterates exceed the divergence threshold although the optimum is finite
+	c, A, b = []float64{1, 1}, [][]float64{{1, 0}, {0, 1}}, []float64{1e13, 1}
+	x, y, optimalValue, err = lp.InteriorPoint(c, A, b)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{1e13, 1}, x, 1e-3)
+	assert.InDelta(t, 1e13+1, optimalValue, 1e-3)
+	assert.InDeltaSlice(t, []float64{1, 1}, y, epsilon)
+}
+
+func TestInteriorPointAgreesWithMaximize(t *testing.T) {
+	random := rand.New(rand.NewSource(44))
+	for range 30 {
+		m, n := 1+random.Intn(10), 1+random.Intn(10)
+		c, A, b := randomLP(random, m, n, 4)
+		for i := range m {
+			b[i] = math.Abs(b[i])
+		}
+
+		_, expected, expectedErr := lp.Maximize(c, A, b)
+		x, y, optimalValue, err := lp.InteriorPoint(c, A, b)
+		if expectedErr != nil {
+			// b >= 0, so the program is feasible
+			assert.ErrorIs(t, expectedErr, lp.ErrUnbounded)
+			assert.ErrorIs(t, err, lp.ErrUnbounded)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.InDelta(t, expected, optimalValue, 1e-6)
+		assertOptimalPair(t, c, A, b, x, y)
+
+		// the optimal vertex of the crossover is an optimal basis of Simplex
+		vertex, y, optimalValue, basis, err := lp.Crossover(c, A, b, x)
+		assert.NoError(t, err)
+		assert.InDelta(t, expected, optimalValue, 1e-6)
+		assertOptimalPair(t, c, A, b, vertex, y)
+		A_full := append(append([][]float64{}, A...), util.NegMatrix(util.IdentityMatrix(n))...)
+		assert.InDeltaSlice(t, vertex, solveBasis(t, A_full, append(append([]float64{}, b...), make([]float64, n)...), basis), 1e-6)
+	}
+}
+
+func solveBasis(t *testing.T, A [][]float64, b []float64, basis []int) []float64 {
+	x, err := lp.SolveLinearSystem(util.GetRows(A, basis), util.GetValues(b, basis))
+	assert.NoError(t, err)
+	return x
+}
@@ -0,0 +1,111 @@
+package lp
+
+import (
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+LU factorization PA = LU of a square matrix A with partial pivoting (maximum absolute value pivot rule).
+L is unit lower triangular and stored together with U in a single matrix.
+Once computed, systems with A and with its transpose are solved in O(n^2).
+*/
+type LU[T any] struct {
+	f numbers.Field[T]
+	// L below and U on and above the diagonal
+	lu [][]T
+	// row i of PA is row perm[i] of A
+	perm []int
+}
+
+// Factorizes A where pivots within util.DefaultTolerance of zero count as zero.
+func FactorizeLU(A [][]float64) (*LU[float64], error) {
+	return FactorizeLUOver(numbers.Float64{Tolerance: util.DefaultTolerance}, A)
+}
+
+// Factorizes A in an arbitrary field.
+func FactorizeLUOver[T any](f numbers.Field[T], A [][]T) (*LU[T], error) {
+	N := len(A)
+	if N == 0 || len(A[0]) != N {
+		return nil, ErrInvalidInput
+	}
+
+	lu := make([][]T, N)
+	perm := make([]int, N)
+	for i := range A {
+		lu[i] = make([]T, N)
+		copy(lu[i], A[i])
+		perm[i] = i
+	}
+
+	for col := 0; col < N; col++ {
+		i_max := col
+		for row := col + 1; row < N; row++ {
+			if f.Cmp(f.Abs(lu[row][col]), f.Abs(lu[i_max][col])) > 0 {
+				i_max = row
+			}
+		}
+		if f.Sign(lu[i_max][col]) == 0 {
+			return nil, ErrSingularMatrix
+		}
+		lu[col], lu[i_max] = lu[i_max], lu[col]
+		perm[col], perm[i_max] = perm[i_max], perm[col]
+
+		for row := col + 1; row < N; row++ {
+			ratio := f.Div(lu[row][col], lu[col][col])
+			for j := col + 1; j < N; j++ {
+				lu[row][j] = f.Sub(lu[row][j], f.Mul(ratio, lu[col][j]))
+			}
+			lu[row][col] = ratio
+		}
+	}
+	return &LU[T]{f: f, lu: lu, perm: perm}, nil
+}
+
+// Solves A@x = b.
+func (d *LU[T]) Solve(b []T) (x []T) {
+	f := d.f
+	N := len(d.lu)
+	x = make([]T, N)
+	// forward substitution L@z = P@b
+	for i := 0; i < N; i++ {
+		x[i] = b[d.perm[i]]
+		for j := 0; j < i; j++ {
+			x[i] = f.Sub(x[i], f.Mul(d.lu[i][j], x[j]))
+		}
+	}
+	// back substitution U@x = z
+	for i := N - 1; i >= 0; i-- {
+		for j := i + 1; j < N; j++ {
+			x[i] = f.Sub(x[i], f.Mul(d.lu[i][j], x[j]))
+		}
+		x[i] = f.Div(x[i], d.lu[i][i])
+	}
+	return x
+}
+
+// Solves A^T@x = b, using A^T = U^T@L^T@P.
+func (d *LU[T]) SolveTransposed(b []T) (x []T) {
+	f := d.f
+	N := len(d.lu)
+	v := make([]T, N)
+	// forward substitution U^T@z = b
+	for i := 0; i < N; i++ {
+		v[i] = b[i]
+		for j := 0; j < i; j++ {
+			v[i] = f.Sub(v[i], f.Mul(d.lu[j][i], v[j]))
+		}
+		v[i] = f.Div(v[i], d.lu[i][i])
+	}
+	// back substitution L^T@v = z
+	for i := N - 1; i >= 0; i-- {
+		for j := i + 1; j < N; j++ {
+			v[i] = f.Sub(v[i], f.Mul(d.lu[j][i], v[j]))
+		}
+	}
+	x = make([]T, N)
+	for i := range v {
+		x[d.perm[i]] = v[i]
+	}
+	return x
+}
@@ -0,0 +1,397 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var ErrInvalidModel = errors.New("invalid model")
+
+type Sense int
+
+const (
+	Maximization Sense = iota
+	Minimization
+)
+
+/*
+A variable of a Model with Lower <= x <= Upper. Use math.Inf(-1) and math.Inf(1) for missing bounds,
+so a free variable has bounds (-Inf, +Inf) and a non-negative one (0, +Inf).
+Integer variables make the model a mixed integer program that Solve hands to BranchAndBound.
+*/
+type Variable struct {
+	Name    string
+	Lower   float64
+	Upper   float64
+	Integer bool
+	index   int
+}
+
+// A summand of a linear expression, see T.
+type Term struct {
+	Coefficient float64
+	Variable    *Variable
+}
+
+// For shorter notation
+func T(coefficient float64, v *Variable) Term {
+	return Term{Coefficient: coefficient, Variable: v}
+}
+
+type Relation int
+
+const (
+	LessEqual Relation = iota
+	GreaterEqual
+	Equal
+)
+
+// A constraint Lower <= sum of Terms <= Upper of a Model, where one side may be infinite.
+type Constraint struct {
+	Name  string
+	Terms []Term
+	Lower float64
+	Upper float64
+}
+
+/*
+A linear program with named, bounded variables and general constraints.
+
+Solve transforms it into the natural form max c@x s.t. A@x <= b, x >= 0 that Maximize expects
+and maps the solution back to the variables of the model.
+*/
+type Model struct {
+	Variables   []*Variable
+	Constraints []*Constraint
+	Sense       Sense
+	Objective   []Term
+}
+
+func NewModel() *Model {
+	return &Model{}
+}
+
+// Adds a variable with Lower <= x <= Upper to the model.
+func (m *Model) AddVariable(name string, lower float64, upper float64) *Variable {
+	v := &Variable{Name: name, Lower: lower, Upper: upper, index: len(m.Variables)}
+	m.Variables = append(m.Variables, v)
+	return v
+}
+
+// Adds a variable with x >= 0 to the model.
+func (m *Model) AddNonNegativeVariable(name string) *Variable {
+	return m.AddVariable(name, 0, math.Inf(1))
+}
+
+// Adds a variable with Lower <= x <= Upper to the model that may only take integral values.
+func (m *Model) AddIntegerVariable(name string, lower float64, upper float64) *Variable {
+	v := m.AddVariable(name, lower, upper)
+	v.Integer = true
+	return v
+}
+
+// Adds a variable without bounds to the model.
+func (m *Model) AddFreeVariable(name string) *Variable {
+	return m.AddVariable(name, math.Inf(-1), math.Inf(1))
+}
+
+// Adds the constraint terms <= rhs, terms >= rhs or terms = rhs to the model.
+func (m *Model) AddConstraint(name string, terms []Term, relation Relation, rhs float64) *Constraint {
+	lower, upper := math.Inf(-1), math.Inf(1)
+	switch relation {
+	case LessEqual:
+		upper = rhs
+	case GreaterEqual:
+		lower = rhs
+	case Equal:
+		lower, upper = rhs, rhs
+	}
+	return m.AddRange(name, lower, terms, upper)
+}
+
+// Adds the constraint lower <= terms <= upper to the model.
+func (m *Model) AddRange(name string, lower float64, terms []Term, upper float64) *Constraint {
+	c := &Constraint{Name: name, Terms: terms, Lower: lower, Upper: upper}
+	m.Constraints = append(m.Constraints, c)
+	return c
+}
+
+func (m *Model) SetObjective(sense Sense, terms []Term) {
+	m.Sense = sense
+	m.Objective = terms
+}
+
+/*
+Objective is the objective value of the solution. For mixed integer programs Bound is the best bound
+on the optimal value that BranchAndBound proved (an upper bound when maximizing, a lower bound when
+minimizing) and Gap the relative gap between both, for linear programs they are Objective and zero.
+*/
+type Solution struct {
+	Objective float64
+	Bound     float64
+	Gap       float64
+	// the value of every variable by its name
+	Values map[string]float64
+	values []float64
+}
+
+// The value of v in the solution.
+func (s *Solution) Value(v *Variable) float64 {
+	return s.values[v.index]
+}
+
+/*
+How a variable x of the model is expressed by the non-negative columns of the natural form:
+x = Offset + Sign*x_Column, or x = x_Column - x_(Column+1) for free variables.
+*/
+type columnMapping struct {
+	Column int
+	Sign   float64
+	Offset float64
+	Free   bool
+}
+
+/*
+The model in natural form max c@x s.t. A@x <= b, x >= 0 together with what is needed to
+translate its solutions back: the objective of the model is c@x + constant (negated for minimization).
+*/
+type naturalForm struct {
+	c        []float64
+	A        [][]float64
+	b        []float64
+	constant float64
+	columns  []columnMapping
+	// which columns must be integral
+	integer []bool
+	// where every row of A comes from: the bounds of a variable or a constraint
+	rowVariables   []*Variable
+	rowConstraints []*Constraint
+}
+
+/*
+Returns the model in natural form max c@x s.t. A@x <= b, x >= 0. Variables with a lower bound are
+shifted, variables with only an upper bound are mirrored and free variables are split into a
+positive and a negative part. Upper bounds, ranges and equalities become one or two rows each.
+*/
+func (m *Model) NaturalForm() (c []float64, A [][]float64, b []float64, err error) {
+	form, err := m.naturalForm()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return form.c, form.A, form.b, nil
+}
+
+func (m *Model) naturalForm() (form naturalForm, err error) {
+	n := 0
+	form.columns = make([]columnMapping, len(m.Variables))
+	for i, v := range m.Variables {
+		if v.index != i {
+			return form, fmt.Errorf("%w: variable %v was not added by AddVariable", ErrInvalidModel, v.Name)
+		}
+		lower, upper := v.bounds()
+		if lower > upper {
+			return form, fmt.Errorf("%w: variable %v has lower bound %v above its upper bound %v", ErrInfeasible, v.Name, v.Lower, v.Upper)
+		}
+		switch {
+		case !math.IsInf(lower, -1):
+			form.columns[i] = columnMapping{Column: n, Sign: 1, Offset: lower}
+			form.integer = append(form.integer, v.Integer)
+			n++
+		case !math.IsInf(upper, 1):
+			form.columns[i] = columnMapping{Column: n, Sign: -1, Offset: upper}
+			form.integer = append(form.integer, v.Integer)
+			n++
+		default:
+			form.columns[i] = columnMapping{Column: n, Sign: 1, Free: true}
+			form.integer = append(form.integer, v.Integer, v.Integer)
+			n += 2
+		}
+	}
+
+	// substitutes the columns into terms, returning the row and the constant part
+	substitute := func(terms []Term) (row []float64, constant float64, err error) {
+		row = make([]float64, n)
+		for _, t := range terms {
+			if t.Variable == nil || t.Variable.index >= len(m.Variables) || m.Variables[t.Variable.index] != t.Variable {
+				return nil, 0, fmt.Errorf("%w: term refers to a variable that is not part of the model", ErrInvalidModel)
+			}
+			col := form.columns[t.Variable.index]
+			row[col.Column] += col.Sign * t.Coefficient
+			if col.Free {
+				row[col.Column+1] -= t.Coefficient
+			}
+			constant += t.Coefficient * col.Offset
+		}
+		return row, constant, nil
+	}
+
+	addRow := func(row []float64, rhs float64, v *Variable, con *Constraint) {
+		form.A = append(form.A, row)
+		form.b = append(form.b, rhs)
+		form.rowVariables = append(form.rowVariables, v)
+		form.rowConstraints = append(form.rowConstraints, con)
+	}
+
+	for i, v := range m.Variables {
+		col := form.columns[i]
+		lower, upper := v.bounds()
+		if !col.Free && !math.IsInf(lower, -1) && !math.IsInf(upper, 1) {
+			row := make([]float64, n)
+			row[col.Column] = 1
+			addRow(row, upper-lower, v, nil)
+		}
+	}
+
+	for _, con := range m.Constraints {
+		if con.Lower > con.Upper {
+			return form, fmt.Errorf("%w: constraint %v has lower bound %v above its upper bound %v", ErrInfeasible, con.Name, con.Lower, con.Upper)
+		}
+		row, constant, err := substitute(con.Terms)
+		if err != nil {
+			return form, err
+		}
+		if !math.IsInf(con.Upper, 1) {
+			addRow(row, con.Upper-constant, nil, con)
+		}
+		if !math.IsInf(con.Lower, -1) {
+			negated := make([]float64, n)
+			for j := range row {
+				negated[j] = -row[j]
+			}
+			addRow(negated, constant-con.Lower, nil, con)
+		}
+	}
+
+	// PhaseOne needs at least one row
+	if len(form.A) == 0 {
+		addRow(make([]float64, n), 0, nil, nil)
+	}
+
+	form.c, form.constant, err = substitute(m.Objective)
+	if err != nil {
+		return form, err
+	}
+	if m.Sense == Minimization {
+		for j := range form.c {
+			form.c[j] = -form.c[j]
+		}
+		form.constant = -form.constant
+	}
+	return form, nil
+}
+
+// The bounds of v, rounded to the integers within them for integer variables so that the shifted column is integral as well.
+func (v *Variable) bounds() (lower float64, upper float64) {
+	if v.Integer {
+		return math.Ceil(v.Lower), math.Floor(v.Upper)
+	}
+	return v.Lower, v.Upper
+}
+
+// Translates a solution x of the natural form back to the variables of the model.
+func (form naturalForm) values(x []float64) []float64 {
+	values := make([]float64, len(form.columns))
+	for i, col := range form.columns {
+		values[i] = col.Offset + col.Sign*x[col.Column]
+		if col.Free {
+			values[i] -= x[col.Column+1]
+		}
+	}
+	return values
+}
+
+func (m *Model) Solve(opts ...Option) (*Solution, error) {
+	return m.SolveContext(context.Background(), opts...)
+}
+
+/*
+Same as Solve, but gives up once ctx is done. Like MaximizeContext it then returns the best
+feasible solution found so far, if any, together with an error that matches util.ErrInterrupted.
+*/
+func (m *Model) SolveContext(ctx context.Context, opts ...Option) (*Solution, error) {
+	form, err := m.naturalForm()
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Variables) == 0 {
+		return &Solution{Values: map[string]float64{}}, nil
+	}
+
+	if slices.Contains(form.integer, true) {
+		result, err := BranchAndBoundContext(ctx, form.c, form.A, form.b, form.integer, opts...)
+		if result.X == nil {
+			return nil, err
+		}
+		return m.solution(form, result.X, result.Objective, result.Bound), err
+	}
+
+	x, optimalValue, err := MaximizeContext(ctx, form.c, form.A, form.b, opts...)
+	if x == nil {
+		return nil, err
+	}
+	return m.solution(form, x, optimalValue, optimalValue), err
+}
+
+/*
+Explains why the model is infeasible by an irreducible infeasible subsystem, see IIS: the constraints
+and the variables whose bounds conflict, while leaving out any one of them would make the model feasible.
+Lower bounds (or upper bounds of variables without lower bound) are always considered part of the system,
+so a variable is only listed if the bound on the other side is needed for the conflict.
+Integrality is ignored. If the linear relaxation is feasible, ErrFeasible is returned.
+*/
+func (m *Model) IIS(opts ...Option) (constraints []*Constraint, variables []*Variable, err error) {
+	form, err := m.naturalForm()
+	if errors.Is(err, ErrInfeasible) {
+		// a single variable or constraint with contradicting bounds
+		for _, v := range m.Variables {
+			if lower, upper := v.bounds(); lower > upper {
+				return nil, []*Variable{v}, nil
+			}
+		}
+		for _, con := range m.Constraints {
+			if con.Lower > con.Upper {
+				return []*Constraint{con}, nil, nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, _, err := IIS(form.A, form.b, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, i := range rows {
+		if con := form.rowConstraints[i]; con != nil && !slices.Contains(constraints, con) {
+			constraints = append(constraints, con)
+		}
+		if v := form.rowVariables[i]; v != nil {
+			variables = append(variables, v)
+		}
+	}
+	return constraints, variables, nil
+}
+
+func (m *Model) solution(form naturalForm, x []float64, optimalValue float64, bound float64) *Solution {
+	objective := optimalValue + form.constant
+	bound += form.constant
+	if m.Sense == Minimization {
+		objective = -objective
+		bound = -bound
+	}
+	solution := &Solution{
+		Objective: objective,
+		Bound:     bound,
+		Gap:       math.Abs(bound-objective) / max(1, math.Abs(objective)),
+		Values:    make(map[string]float64, len(m.Variables)),
+		values:    form.values(x),
+	}
+	for i, v := range m.Variables {
+		solution.Values[v.Name] = solution.values[i]
+	}
+	return solution
+}
@@ -0,0 +1,66 @@
+package lp_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddFreeVariable("x")
+	y := m.AddVariable("y", 0, 3)
+	z := m.AddVariable("z", math.Inf(-1), 5)
+
+	m.AddConstraint("demand", []lp.Term{lp.T(1, x), lp.T(1, y)}, lp.GreaterEqual, 2)
+	m.AddConstraint("balance", []lp.Term{lp.T(1, x), lp.T(-1, y)}, lp.Equal, 1)
+	m.AddRange("window", 1, []lp.Term{lp.T(1, x), lp.T(1, z)}, 4)
+	m.SetObjective(lp.Minimization, []lp.Term{lp.T(1, x), lp.T(3, y), lp.T(-1, z)})
+
+	solution, err := m.Solve()
+	assert.NoError(t, err)
+
+	assert.InDelta(t, 0.5, solution.Objective, epsilon)
+	assert.InDelta(t, 1.5, solution.Value(x), epsilon)
+	assert.InDelta(t, 0.5, solution.Values["y"], epsilon)
+	assert.InDelta(t, 2.5, solution.Value(z), epsilon)
+}
+
+func TestModelNaturalForm(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddVariable("x", 1, 4)
+	y := m.AddNonNegativeVariable("y")
+	m.AddConstraint("c", []lp.Term{lp.T(2, x), lp.T(1, y)}, lp.LessEqual, 10)
+	m.SetObjective(lp.Maximization, []lp.Term{lp.T(1, x), lp.T(1, y)})
+
+	c, A, b, err := m.NaturalForm()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1}, c)
+	assert.Equal(t, [][]float64{{1, 0}, {2, 1}}, A)
+	assert.Equal(t, []float64{3, 8}, b)
+
+	solution, err := m.Solve()
+	assert.NoError(t, err)
+	assert.InDelta(t, 9, solution.Objective, epsilon)
+	assert.InDelta(t, 1, solution.Value(x), epsilon)
+	assert.InDelta(t, 8, solution.Value(y), epsilon)
+}
+
+func TestModelInfeasibleAndUnbounded(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddNonNegativeVariable("x")
+	m.AddConstraint("negative", []lp.Term{lp.T(1, x)}, lp.LessEqual, -1)
+	m.SetObjective(lp.Maximization, []lp.Term{lp.T(1, x)})
+
+	_, err := m.Solve()
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+
+	m = lp.NewModel()
+	x = m.AddNonNegativeVariable("x")
+	m.SetObjective(lp.Maximization, []lp.Term{lp.T(1, x)})
+
+	_, err = m.Solve()
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+}
@@ -0,0 +1,562 @@
+package lp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFormat = errors.New("invalid file format")
+
+type MPSFormat int
+
+const (
+	// fields separated by whitespace, names without spaces of any length
+	FreeMPS MPSFormat = iota
+	// fields at fixed columns, names of at most 8 characters
+	FixedMPS
+)
+
+// Values of at least this size stand for infinity in MPS files, as most solvers write them.
+const mpsInfinity = 1e30
+
+/*
+Reads a linear or mixed integer program in MPS format into a model, for example an instance of Netlib
+or MIPLIB. The first N row is the objective, further N rows are ignored. Columns between the
+'INTORG' and 'INTEND' markers are integer variables. Only the first RHS, RANGES and BOUNDS set is
+used. Bounds follow the usual conventions: variables are non-negative unless bounded otherwise, and an
+UP bound below zero on a variable with lower bound zero makes the variable unbounded below.
+The objective is minimized, unless an OBJSENSE section says MAX.
+A right hand side for the objective row (a constant in the objective) is not supported.
+*/
+func ReadMPS(r io.Reader, format MPSFormat) (*Model, error) {
+	p := mpsReader{
+		format:      format,
+		m:           NewModel(),
+		constraints: map[string]*Constraint{},
+		rowTypes:    map[string]string{},
+		variables:   map[string]*Variable{},
+		rhs:         map[*Constraint]float64{},
+		ranges:      map[*Constraint]float64{},
+	}
+	p.m.Sense = Minimization
+
+	scanner := bufio.NewScanner(r)
+	section := ""
+	for scanner.Scan() {
+		p.line++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || line[0] == '*' {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			fields := strings.Fields(line)
+			section = strings.ToUpper(fields[0])
+			switch section {
+			case "NAME", "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS":
+			case "OBJSENSE":
+				// free MPS allows the sense on the same line
+				if len(fields) > 1 {
+					if err := p.sense(fields[1]); err != nil {
+						return nil, err
+					}
+				}
+			case "ENDATA":
+				return p.finish()
+			default:
+				return nil, p.errorf("unknown section %v", fields[0])
+			}
+			continue
+		}
+
+		var err error
+		switch section {
+		case "OBJSENSE":
+			err = p.sense(strings.TrimSpace(line))
+		case "ROWS":
+			err = p.row(p.fields(line, 1, 2))
+		case "COLUMNS":
+			err = p.column(p.fields(line, 2, 6))
+		case "RHS":
+			err = p.values(p.fields(line, 2, 6), &p.rhsSet, p.rhs)
+		case "RANGES":
+			err = p.values(p.fields(line, 2, 6), &p.rangeSet, p.ranges)
+		case "BOUNDS":
+			err = p.bound(p.fields(line, 1, 4))
+		default:
+			err = p.errorf("data outside of a section")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, p.errorf("missing ENDATA")
+}
+
+type mpsReader struct {
+	format      MPSFormat
+	m           *Model
+	line        int
+	objective   string
+	constraints map[string]*Constraint
+	rowTypes    map[string]string
+	variables   map[string]*Variable
+	integer     bool
+	rhsSet      *string
+	rangeSet    *string
+	boundSet    *string
+	rhs         map[*Constraint]float64
+	ranges      map[*Constraint]float64
+}
+
+func (p *mpsReader) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %v: %v", ErrInvalidFormat, p.line, fmt.Sprintf(format, args...))
+}
+
+/*
+The fields first to last of a data line, numbered like the fields of fixed MPS. In free MPS, the
+fields are separated by whitespace instead, so optional set names are missing rather than empty.
+*/
+func (p *mpsReader) fields(line string, first int, last int) []string {
+	if p.format == FreeMPS {
+		return strings.Fields(line)
+	}
+	columns := [][2]int{{1, 3}, {4, 12}, {14, 22}, {24, 36}, {39, 47}, {49, 61}}
+	var fields []string
+	for _, c := range columns[first-1 : last] {
+		field := ""
+		if c[0] < len(line) {
+			field = strings.TrimSpace(line[c[0]:min(c[1], len(line))])
+		}
+		fields = append(fields, field)
+	}
+	for len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	return fields
+}
+
+func (p *mpsReader) number(field string) (float64, error) {
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, p.errorf("%v is not a number", field)
+	}
+	if math.Abs(v) >= mpsInfinity {
+		return math.Inf(int(math.Copysign(1, v))), nil
+	}
+	return v, nil
+}
+
+func (p *mpsReader) sense(field string) error {
+	switch strings.ToUpper(field) {
+	case "MAX", "MAXIMIZE":
+		p.m.Sense = Maximization
+	case "MIN", "MINIMIZE":
+		p.m.Sense = Minimization
+	default:
+		return p.errorf("unknown objective sense %v", field)
+	}
+	return nil
+}
+
+func (p *mpsReader) row(fields []string) error {
+	if len(fields) != 2 {
+		return p.errorf("a row needs a type and a name")
+	}
+	kind, name := strings.ToUpper(fields[0]), fields[1]
+	if _, ok := p.rowTypes[name]; ok {
+		return p.errorf("row %v is defined twice", name)
+	}
+	switch kind {
+	case "N":
+		if p.objective == "" {
+			p.objective = name
+		}
+	case "L", "G", "E":
+		p.constraints[name] = p.m.AddRange(name, math.Inf(-1), nil, math.Inf(1))
+	default:
+		return p.errorf("unknown row type %v", fields[0])
+	}
+	p.rowTypes[name] = kind
+	return nil
+}
+
+func (p *mpsReader) column(fields []string) error {
+	if len(fields) >= 2 && fields[1] == "'MARKER'" {
+		for _, f := range fields[2:] {
+			switch f {
+			case "'INTORG'":
+				p.integer = true
+				return nil
+			case "'INTEND'":
+				p.integer = false
+				return nil
+			}
+		}
+		return p.errorf("unknown marker")
+	}
+	if len(fields) != 3 && len(fields) != 5 {
+		return p.errorf("a column entry needs a column and one or two rows with values")
+	}
+
+	name := fields[0]
+	v, ok := p.variables[name]
+	if !ok {
+		v = p.m.AddNonNegativeVariable(name)
+		v.Integer = p.integer
+		p.variables[name] = v
+	}
+	for k := 1; k < len(fields); k += 2 {
+		value, err := p.number(fields[k+1])
+		if err != nil {
+			return err
+		}
+		row := fields[k]
+		switch {
+		case row == p.objective:
+			p.m.Objective = append(p.m.Objective, T(value, v))
+		case p.constraints[row] != nil:
+			p.constraints[row].Terms = append(p.constraints[row].Terms, T(value, v))
+		case p.rowTypes[row] == "":
+			return p.errorf("unknown row %v", row)
+		}
+	}
+	return nil
+}
+
+// Reads an entry of the RHS or RANGES section.
+func (p *mpsReader) values(fields []string, set **string, values map[*Constraint]float64) error {
+	if p.format == FreeMPS && len(fields)%2 == 0 {
+		// without set name
+		fields = append([]string{""}, fields...)
+	}
+	if len(fields) != 3 && len(fields) != 5 {
+		return p.errorf("an entry needs a set name and one or two rows with values")
+	}
+	if *set == nil {
+		*set = &fields[0]
+	}
+	if **set != fields[0] {
+		return nil
+	}
+	for k := 1; k < len(fields); k += 2 {
+		value, err := p.number(fields[k+1])
+		if err != nil {
+			return err
+		}
+		row := fields[k]
+		switch {
+		case p.constraints[row] != nil:
+			values[p.constraints[row]] = value
+		case row == p.objective && value != 0:
+			return p.errorf("constants in the objective are not supported")
+		case p.rowTypes[row] == "":
+			return p.errorf("unknown row %v", row)
+		}
+	}
+	return nil
+}
+
+func (p *mpsReader) bound(fields []string) error {
+	if len(fields) == 0 {
+		return p.errorf("a bound needs a type")
+	}
+	kind := strings.ToUpper(fields[0])
+	withValue := kind != "FR" && kind != "MI" && kind != "PL" && kind != "BV"
+	if p.format == FreeMPS && (withValue && len(fields) == 3 || !withValue && len(fields) == 2) {
+		// without set name
+		fields = append([]string{fields[0], ""}, fields[1:]...)
+	}
+	if len(fields) < 3 || withValue && len(fields) != 4 {
+		return p.errorf("a bound needs a type, a set name, a column and a value")
+	}
+	if p.boundSet == nil {
+		p.boundSet = &fields[1]
+	}
+	if *p.boundSet != fields[1] {
+		return nil
+	}
+
+	v, ok := p.variables[fields[2]]
+	if !ok {
+		return p.errorf("unknown column %v", fields[2])
+	}
+	var value float64
+	if withValue {
+		var err error
+		if value, err = p.number(fields[3]); err != nil {
+			return err
+		}
+	}
+	switch kind {
+	case "UP", "UI":
+		if value < 0 && v.Lower == 0 {
+			v.Lower = math.Inf(-1)
+		}
+		v.Upper = value
+	case "LO", "LI":
+		v.Lower = value
+	case "FX":
+		v.Lower, v.Upper = value, value
+	case "FR":
+		v.Lower, v.Upper = math.Inf(-1), math.Inf(1)
+	case "MI":
+		v.Lower = math.Inf(-1)
+	case "PL":
+		v.Upper = math.Inf(1)
+	case "BV":
+		v.Lower, v.Upper = 0, 1
+	default:
+		return p.errorf("unknown bound type %v", fields[0])
+	}
+	if kind == "UI" || kind == "LI" || kind == "BV" {
+		v.Integer = true
+	}
+	return nil
+}
+
+/*
+Sets the bounds of the constraints from their type, right hand side and range: a range R turns
+an L row into rhs-|R| <= row <= rhs, a G row into rhs <= row <= rhs+|R| and an E row into
+rhs <= row <= rhs+R or rhs+R <= row <= rhs, depending on the sign of R.
+*/
+func (p *mpsReader) finish() (*Model, error) {
+	for _, con := range p.m.Constraints {
+		rhs := p.rhs[con]
+		r, ranged := p.ranges[con]
+		switch p.rowTypes[con.Name] {
+		case "L":
+			con.Upper = rhs
+			if ranged {
+				con.Lower = rhs - math.Abs(r)
+			}
+		case "G":
+			con.Lower = rhs
+			if ranged {
+				con.Upper = rhs + math.Abs(r)
+			}
+		case "E":
+			con.Lower, con.Upper = rhs, rhs
+			if ranged && r > 0 {
+				con.Upper = rhs + r
+			}
+			if ranged && r < 0 {
+				con.Lower = rhs + r
+			}
+		}
+	}
+	return p.m, nil
+}
+
+/*
+Writes the model in MPS format. The objective row is called OBJ, and an OBJSENSE section is written
+for maximization. Ranges are written as L rows with a range. In fixed MPS, names must not be longer
+than 8 characters, and numbers are shortened to 12 characters. In free MPS, names must not contain spaces.
+*/
+func WriteMPS(w io.Writer, m *Model, format MPSFormat) error {
+	entries, err := columnEntries(m)
+	if err != nil {
+		return err
+	}
+	variables, constraints, err := fileNames(m, func(name string) bool {
+		if format == FixedMPS {
+			return len(name) <= 8 && name[0] != ' '
+		}
+		return !strings.ContainsAny(name, " \t")
+	}, "OBJ")
+	if err != nil {
+		return err
+	}
+	rows := map[*Constraint]string{}
+	for i, con := range m.Constraints {
+		rows[con] = constraints[i]
+	}
+	number := func(v float64) string {
+		if math.IsInf(v, 0) {
+			v = math.Copysign(mpsInfinity, v)
+		}
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		for precision := 12; format == FixedMPS && len(s) > 12; precision-- {
+			s = strconv.FormatFloat(v, 'g', precision, 64)
+		}
+		return s
+	}
+
+	out := bufio.NewWriter(w)
+	line := func(format string, args ...any) {
+		fmt.Fprintf(out, format+"\n", args...)
+	}
+	entry := func(first string, second string, value float64) {
+		line("    %-8s  %-8s  %12s", first, second, number(value))
+	}
+
+	line("NAME")
+	if m.Sense == Maximization {
+		line("OBJSENSE")
+		line("    MAX")
+	}
+	line("ROWS")
+	line(" N  OBJ")
+	for _, con := range m.Constraints {
+		line(" %-2s %v", mpsRowType(con), rows[con])
+	}
+
+	line("COLUMNS")
+	integer := false
+	for i, v := range m.Variables {
+		if v.Integer != integer {
+			integer = v.Integer
+			marker := "'INTEND'"
+			if integer {
+				marker = "'INTORG'"
+			}
+			line("    %-8s  %-8s%17s%v", "MARKER", "'MARKER'", "", marker)
+		}
+		if len(entries[i]) == 0 {
+			entry(variables[i], "OBJ", 0)
+		}
+		for _, e := range entries[i] {
+			row := "OBJ"
+			if e.constraint != nil {
+				row = rows[e.constraint]
+			}
+			entry(variables[i], row, e.coefficient)
+		}
+	}
+	if integer {
+		line("    %-8s  %-8s%17s%v", "MARKER", "'MARKER'", "", "'INTEND'")
+	}
+
+	line("RHS")
+	for _, con := range m.Constraints {
+		rhs := con.Upper
+		if mpsRowType(con) == "G" {
+			rhs = con.Lower
+		}
+		if mpsRowType(con) != "N" && rhs != 0 {
+			entry("RHS", rows[con], rhs)
+		}
+	}
+	line("RANGES")
+	for _, con := range m.Constraints {
+		if mpsRowType(con) == "L" && !math.IsInf(con.Lower, -1) {
+			entry("RNG", rows[con], con.Upper-con.Lower)
+		}
+	}
+
+	line("BOUNDS")
+	for i, v := range m.Variables {
+		bound := func(kind string, value float64) {
+			line(" %-2s %-8s  %-8s  %12s", kind, "BND", variables[i], number(value))
+		}
+		switch {
+		case v.Lower == v.Upper:
+			bound("FX", v.Lower)
+		case math.IsInf(v.Lower, -1) && math.IsInf(v.Upper, 1):
+			line(" FR %-8s  %v", "BND", variables[i])
+		case math.IsInf(v.Lower, -1):
+			line(" MI %-8s  %v", "BND", variables[i])
+			bound("UP", v.Upper)
+		default:
+			// an upper bound below zero would make the lower bound zero -Inf, so it comes first
+			if !math.IsInf(v.Upper, 1) {
+				bound("UP", v.Upper)
+			}
+			if v.Lower != 0 || v.Upper < 0 {
+				bound("LO", v.Lower)
+			}
+		}
+	}
+	line("ENDATA")
+	return out.Flush()
+}
+
+func mpsRowType(con *Constraint) string {
+	switch {
+	case con.Lower == con.Upper:
+		return "E"
+	case !math.IsInf(con.Upper, 1):
+		return "L"
+	case !math.IsInf(con.Lower, -1):
+		return "G"
+	}
+	return "N"
+}
+
+// A coefficient of a variable in the objective (constraint nil) or a constraint.
+type columnEntry struct {
+	constraint  *Constraint
+	coefficient float64
+}
+
+// The coefficients of every variable of the model, objective first, with the terms of each variable in a row summed up.
+func columnEntries(m *Model) ([][]columnEntry, error) {
+	entries := make([][]columnEntry, len(m.Variables))
+	add := func(terms []Term, con *Constraint) error {
+		for _, t := range terms {
+			if t.Variable == nil || t.Variable.index >= len(m.Variables) || m.Variables[t.Variable.index] != t.Variable {
+				return fmt.Errorf("%w: term refers to a variable that is not part of the model", ErrInvalidModel)
+			}
+			column := entries[t.Variable.index]
+			if len(column) > 0 && column[len(column)-1].constraint == con {
+				column[len(column)-1].coefficient += t.Coefficient
+			} else {
+				entries[t.Variable.index] = append(column, columnEntry{constraint: con, coefficient: t.Coefficient})
+			}
+		}
+		return nil
+	}
+	if err := add(m.Objective, nil); err != nil {
+		return nil, err
+	}
+	for _, con := range m.Constraints {
+		if err := add(con.Terms, con); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+/*
+The names under which variables and constraints are written to a file, where variables and
+constraints without a name are called x1, x2, ... and c1, c2, ... after their position. Fails with
+ErrInvalidModel if a name is not valid for the format or used twice, including the reserved names.
+*/
+func fileNames(m *Model, valid func(string) bool, reserved ...string) (variables []string, constraints []string, err error) {
+	used := map[string]bool{}
+	for _, name := range reserved {
+		used[name] = true
+	}
+	name := func(name string, fallback string, k int) (string, error) {
+		if name == "" {
+			name = fmt.Sprintf("%v%v", fallback, k+1)
+		}
+		if !valid(name) || used[name] {
+			return "", fmt.Errorf("%w: the name %q cannot be written to the file", ErrInvalidModel, name)
+		}
+		used[name] = true
+		return name, nil
+	}
+	for k, v := range m.Variables {
+		n, err := name(v.Name, "x", k)
+		if err != nil {
+			return nil, nil, err
+		}
+		variables = append(variables, n)
+	}
+	for k, con := range m.Constraints {
+		n, err := name(con.Name, "c", k)
+		if err != nil {
+			return nil, nil, err
+		}
+		constraints = append(constraints, n)
+	}
+	return variables, constraints, nil
+}
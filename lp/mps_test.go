@@ -0,0 +1,181 @@
+package lp_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/stretchr/testify/assert"
+)
+
+// A mixed integer program with every kind of bound and constraint.
+func fileExampleModel() *lp.Model {
+	m := lp.NewModel()
+	x := m.AddVariable("x", 1, 4)
+	y := m.AddFreeVariable("y")
+	z := m.AddIntegerVariable("z", 0, 10)
+	w := m.AddVariable("w", math.Inf(-1), -1)
+	v := m.AddVariable("v", -2, -1)
+	u := m.AddVariable("u", 2, 2)
+	m.AddConstraint("cap", []lp.Term{lp.T(1, x), lp.T(2, y), lp.T(1.5, z)}, lp.LessEqual, 12)
+	m.AddConstraint("demand", []lp.Term{lp.T(1, x), lp.T(-1, w)}, lp.GreaterEqual, 2)
+	m.AddConstraint("balance", []lp.Term{lp.T(1, y), lp.T(-1, z), lp.T(1, v), lp.T(1, u)}, lp.Equal, 0.25)
+	m.AddRange("window", -3, []lp.Term{lp.T(1, y), lp.T(1e-7, w)}, 5)
+	m.SetObjective(lp.Maximization, []lp.Term{lp.T(3, x), lp.T(1, y), lp.T(2, z), lp.T(1, w)})
+	return m
+}
+
+func assertSameModel(t *testing.T, expected *lp.Model, actual *lp.Model) {
+	t.Helper()
+	assert.Equal(t, expected.Sense, actual.Sense)
+	terms := func(terms []lp.Term) map[string]float64 {
+		byName := map[string]float64{}
+		for _, term := range terms {
+			byName[term.Variable.Name] += term.Coefficient
+		}
+		return byName
+	}
+	assert.Equal(t, terms(expected.Objective), terms(actual.Objective))
+	if assert.Len(t, actual.Variables, len(expected.Variables)) {
+		for i, v := range expected.Variables {
+			assert.Equal(t, v.Name, actual.Variables[i].Name)
+			assert.Equal(t, v.Lower, actual.Variables[i].Lower, v.Name)
+			assert.Equal(t, v.Upper, actual.Variables[i].Upper, v.Name)
+			assert.Equal(t, v.Integer, actual.Variables[i].Integer, v.Name)
+		}
+	}
+	if assert.Len(t, actual.Constraints, len(expected.Constraints)) {
+		for i, con := range expected.Constraints {
+			assert.Equal(t, con.Name, actual.Constraints[i].Name)
+			assert.Equal(t, con.Lower, actual.Constraints[i].Lower, con.Name)
+			assert.Equal(t, con.Upper, actual.Constraints[i].Upper, con.Name)
+			assert.Equal(t, terms(con.Terms), terms(actual.Constraints[i].Terms), con.Name)
+		}
+	}
+}
+
+func TestMPSRoundTrip(t *testing.T) {
+	for _, format := range []lp.MPSFormat{lp.FreeMPS, lp.FixedMPS} {
+		m := fileExampleModel()
+		var out bytes.Buffer
+		assert.NoError(t, lp.WriteMPS(&out, m, format))
+
+		read, err := lp.ReadMPS(&out, format)
+		assert.NoError(t, err)
+		assertSameModel(t, m, read)
+	}
+}
+
+func TestReadFixedMPS(t *testing.T) {
+	// max 3x + 2y s.t. x + y <= 4, x + 3y <= 6, x <= 3, written the classic way as minimization
+	file := `NAME          EXAMPLE
+* a comment
+ROWS
+ N  COST
+ L  LIM1
+ L  LIM2
+COLUMNS
+    X         COST                -3   LIM1                 1
+    X         LIM2                 1
+    Y         COST                -2   LIM1                 1
+    Y         LIM2                 3
+RHS
+    RHS       LIM1                 4   LIM2                 6
+    OTHER     LIM1               100
+BOUNDS
+ UP BND       X                    3
+ENDATA
+`
+	m, err := lp.ReadMPS(strings.NewReader(file), lp.FixedMPS)
+	assert.NoError(t, err)
+	assert.Equal(t, lp.Minimization, m.Sense)
+	assert.Len(t, m.Variables, 2)
+	assert.Len(t, m.Constraints, 2)
+
+	solution, err := m.Solve()
+	assert.NoError(t, err)
+	assert.InDelta(t, -11, solution.Objective, epsilon)
+	assert.InDelta(t, 3, solution.Values["X"], epsilon)
+	assert.InDelta(t, 1, solution.Values["Y"], epsilon)
+
+	c, A, b, err := m.NaturalForm()
+	assert.NoError(t, err)
+	_, optimalValue, err := lp.Maximize(c, A, b)
+	assert.NoError(t, err)
+	assert.InDelta(t, 11, optimalValue, epsilon)
+}
+
+func TestReadFreeMPS(t *testing.T) {
+	file := `NAME example
+OBJSENSE MAX
+ROWS
+ N obj
+ N unused
+ E equal
+ G at_least
+COLUMNS
+ MARKER 'MARKER' 'INTORG'
+ a_long_name obj 1 equal 1
+ MARKER 'MARKER' 'INTEND'
+ b obj 1 at_least 1
+ b unused 5
+ c obj 0
+RHS
+ equal 2 at_least 1
+RANGES
+ equal -1.5 at_least 2
+BOUNDS
+ UP a_long_name 10
+ UP b -1
+ BV c
+ENDATA
+`
+	m, err := lp.ReadMPS(strings.NewReader(file), lp.FreeMPS)
+	assert.NoError(t, err)
+	assert.Equal(t, lp.Maximization, m.Sense)
+
+	a, b, c := m.Variables[0], m.Variables[1], m.Variables[2]
+	assert.True(t, a.Integer)
+	assert.False(t, b.Integer)
+	assert.Equal(t, []float64{0, 10}, []float64{a.Lower, a.Upper})
+	assert.Equal(t, []float64{math.Inf(-1), -1}, []float64{b.Lower, b.Upper})
+	assert.Equal(t, []float64{0, 1}, []float64{c.Lower, c.Upper})
+	assert.True(t, c.Integer)
+
+	assert.Equal(t, []float64{0.5, 2}, []float64{m.Constraints[0].Lower, m.Constraints[0].Upper})
+	assert.Equal(t, []float64{1, 3}, []float64{m.Constraints[1].Lower, m.Constraints[1].Upper})
+}
+
+func TestReadMPSErrors(t *testing.T) {
+	for _, file := range []string{
+		"ROWS\n N obj\nCOLUMNS\n x obj 1\n",
+		"ROWS\n X obj\nENDATA\n",
+		"ROWS\n N obj\nCOLUMNS\n x missing 1\nENDATA\n",
+		"ROWS\n N obj\nCOLUMNS\n x obj one\nENDATA\n",
+		"ROWS\n N obj\nRHS\n rhs obj 3\nENDATA\n",
+		"ROWS\n N obj\nCOLUMNS\n x obj 1\nBOUNDS\n XX bnd x 1\nENDATA\n",
+		"SECTION\nENDATA\n",
+	} {
+		_, err := lp.ReadMPS(strings.NewReader(file), lp.FreeMPS)
+		assert.ErrorIs(t, err, lp.ErrInvalidFormat, file)
+	}
+}
+
+func TestWriteMPSNames(t *testing.T) {
+	m := lp.NewModel()
+	x := m.AddNonNegativeVariable("")
+	m.AddConstraint("", []lp.Term{lp.T(1, x)}, lp.LessEqual, 1)
+	var out bytes.Buffer
+	assert.NoError(t, lp.WriteMPS(&out, m, lp.FixedMPS))
+	assert.Contains(t, out.String(), " L  c1\n")
+	assert.Contains(t, out.String(), "    x1        c1                   1\n")
+
+	m.AddNonNegativeVariable("a_long_name")
+	assert.ErrorIs(t, lp.WriteMPS(&out, m, lp.FixedMPS), lp.ErrInvalidModel)
+	assert.NoError(t, lp.WriteMPS(&out, m, lp.FreeMPS))
+
+	m.AddNonNegativeVariable("x1")
+	assert.ErrorIs(t, lp.WriteMPS(&out, m, lp.FreeMPS), lp.ErrInvalidModel)
+}
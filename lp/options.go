@@ -0,0 +1,171 @@
+package lp
+
+import (
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+// Configures the solvers of this package. The zero configuration is used when no options are given.
+type Option func(*options)
+
+type options struct {
+	observer      trace.Observer
+	tolerance     util.Tolerance
+	refactorEvery int
+	branchingRule BranchingRule
+	nodeSelection NodeSelection
+	nodeLimit     int
+	gapTolerance  float64
+	cutFamily     CutFamily
+	cutRounds     int
+	pivotRule     PivotRule
+	presolve      bool
+	maxIterations int
+}
+
+func collectOptions(opts []Option) (o options) {
+	o.tolerance = util.DefaultTolerance
+	o.refactorEvery = defaultRefactorEvery
+	o.maxIterations = defaultMaxIterations
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// Reports the progress of the solver to the given observer, see NewTableObserver and trace.SlogObserver.
+func WithObserver(observer trace.Observer) Option {
+	return func(o *options) {
+		o.observer = observer
+	}
+}
+
+/*
+Sets the tolerance used by the float64 solvers to decide signs and equality, for example whether
+a y value is negative or a pivot is zero. Defaults to util.DefaultTolerance.
+The solvers working on an arbitrary field use the comparisons of that field instead.
+*/
+func WithTolerance(tolerance util.Tolerance) Option {
+	return func(o *options) {
+		o.tolerance = tolerance
+	}
+}
+
+/*
+Sets after how many basis updates RevisedSimplex computes a fresh LU factorization of the basis
+instead of extending its product form further. Smaller values cost more factorizations,
+larger values longer eta files and more accumulated rounding errors. Defaults to 50.
+*/
+func WithRefactorization(every int) Option {
+	return func(o *options) {
+		o.refactorEvery = max(every, 1)
+	}
+}
+
+// Sets on which fractional variable BranchAndBound branches. Defaults to FirstFractional.
+func WithBranchingRule(rule BranchingRule) Option {
+	return func(o *options) {
+		o.branchingRule = rule
+	}
+}
+
+// Sets which open node BranchAndBound solves next. Defaults to BestBound.
+func WithNodeSelection(selection NodeSelection) Option {
+	return func(o *options) {
+		o.nodeSelection = selection
+	}
+}
+
+// Makes BranchAndBound give up after solving the given number of nodes. Zero means no limit, the default.
+func WithNodeLimit(nodes int) Option {
+	return func(o *options) {
+		o.nodeLimit = nodes
+	}
+}
+
+/*
+Makes BranchAndBound prune every node that cannot improve the incumbent by more than the given
+relative gap, see MIPResult. Defaults to zero, which proves optimality.
+*/
+func WithGapTolerance(gap float64) Option {
+	return func(o *options) {
+		o.gapTolerance = gap
+	}
+}
+
+/*
+Makes BranchAndBound add up to the given number of rounds of cuts of the family to every node
+before it branches, and configures CuttingPlane. By default BranchAndBound does not cut.
+*/
+func WithCuts(family CutFamily, rounds int) Option {
+	return func(o *options) {
+		o.cutFamily = family
+		o.cutRounds = rounds
+	}
+}
+
+/*
+Sets on which improving row Simplex and DualSimplex pivot, for example Dantzig{}, SteepestEdge{},
+&Devex{} or &PartialPricing{Segment: 10}. Defaults to Bland{}. Whatever the rule, the solvers
+fall back to Bland's rule while pivots are degenerate, so they never cycle.
+The rule is reset at the start of every solve, so stateful rules must not be shared between solves
+that run concurrently.
+*/
+func WithPivotRule(rule PivotRule) Option {
+	return func(o *options) {
+		o.pivotRule = rule
+	}
+}
+
+/*
+Makes Simplex, RevisedSimplex, DualSimplex, BoundedSimplex and InteriorPoint give up with ErrMaxIterationsReached
+after the given number of iterations. Simplex and RevisedSimplex return their current vertex then,
+like when they are interrupted. Defaults to 1000, zero or less means no limit.
+*/
+func WithMaxIterations(iterations int) Option {
+	return func(o *options) {
+		o.maxIterations = iterations
+	}
+}
+
+/*
+Makes Maximize and Minimize reduce the program with Presolve first and solve the rest with
+BoundedSimplex. Worth it for programs with many redundant rows or fixed variables.
+*/
+func WithPresolve() Option {
+	return func(o *options) {
+		o.presolve = true
+	}
+}
+
+// The pivot rule of the options, reset for a new solve.
+func (o options) pivoting() PivotRule {
+	rule := o.pivotRule
+	if rule == nil {
+		rule = Bland{}
+	}
+	rule.Reset()
+	return rule
+}
+
+// Whether a solver has used up the iterations it may take, when it is in the given one.
+func (o options) exhausted(iteration int) bool {
+	return o.maxIterations > 0 && iteration > o.maxIterations
+}
+
+// The float64 field the float64 solvers compute in.
+func (o options) float64Field() numbers.Float64 {
+	return numbers.Float64{Tolerance: o.tolerance}
+}
+
+// Whether anybody listens. Used to skip building events that nobody would look at.
+func (o options) tracing() bool {
+	return o.observer != nil
+}
+
+func (o options) observe(e trace.Event) {
+	if o.observer != nil {
+		o.observer.Observe(e)
+	}
+}
@@ -1,16 +1,46 @@
 package lp
 
 import (
+	"context"
 	"errors"
 	"slices"
 
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
-// Returns a feasible start basis on the system Ax <= b, x >= 0, or indicates that the system is infeasible.
-func PhaseOne(A [][]float64, b []float64, useDualSimplex bool) (basis []int, feasible bool, err error) {
-	pos_rows := util.FindAll(b, func(row float64) bool { return row >= 0 })
-	neg_rows := util.FindAll(b, func(row float64) bool { return row < 0 })
+/*
+Returns a feasible start basis on the system Ax <= b, x >= 0, or indicates that the system is infeasible.
+The basis consists of row indices of the system [A; -I]@x <= [b; 0], where row len(A)+j stands for x_j >= 0.
+
+If the system is infeasible, farkas is a certificate of that: a vector y >= 0 with y@A >= 0 and y@b < 0,
+since every x >= 0 with Ax <= b would give 0 <= y@A@x <= y@b < 0. The rows with y_i > 0 conflict.
+
+With useDualSimplex the dual simplex is run on [A; -I]@x <= [b; 0] with objective max -sum(x), starting
+from x = 0, whose basis is dual feasible. Otherwise the primal simplex solves an auxiliary problem
+that penalizes the violation of the rows with negative right hand side.
+*/
+func PhaseOne(A [][]float64, b []float64, useDualSimplex bool, opts ...Option) (basis []int, feasible bool, farkas []float64, err error) {
+	return PhaseOneContext(context.Background(), A, b, useDualSimplex, opts...)
+}
+
+/*
+Same as PhaseOne, but gives up once ctx is done. Since an interrupted auxiliary problem
+does not tell whether the system is feasible, no basis is returned in that case, only an
+error that matches util.ErrInterrupted.
+*/
+func PhaseOneContext(ctx context.Context, A [][]float64, b []float64, useDualSimplex bool, opts ...Option) (basis []int, feasible bool, farkas []float64, err error) {
+	return PhaseOneOver(ctx, collectOptions(opts).float64Field(), A, b, useDualSimplex, opts...)
+}
+
+// Same as PhaseOneContext, but in the arithmetic of an arbitrary field.
+func PhaseOneOver[T any](ctx context.Context, f numbers.Field[T], A [][]T, b []T, useDualSimplex bool, opts ...Option) (basis []int, feasible bool, farkas []T, err error) {
+	if useDualSimplex {
+		return phaseOneDual(ctx, f, A, b, opts...)
+	}
+
+	pos_rows := util.FindAll(b, func(row T) bool { return f.Sign(row) >= 0 })
+	neg_rows := util.FindAll(b, func(row T) bool { return f.Sign(row) < 0 })
 
 	m := len(A)
 	n := len(A[0])
@@ -23,32 +53,32 @@ func PhaseOne(A [][]float64, b []float64, useDualSimplex bool) (basis []int, fea
 	A_minus := util.GetRows(A, neg_rows)
 	b_minus := util.GetValues(b, neg_rows)
 
-	A_negated := util.NegMatrix(A_minus)
-	b_negated := util.Neg(b_minus)
+	A_negated := negMatrix(f, A_minus)
+	b_negated := negVec(f, b_minus)
 
-	penalty_A := util.MatMul(util.Transpose(A_minus), util.Ones(m_minus))
-	penalty_slack := util.Ones(m_minus)
+	penalty_A := sumRows(f, n, A_minus)
+	penalty_slack := filled(m_minus, f.One())
 	c := slices.Concat(penalty_A, penalty_slack)
-	c = util.Neg(c)
+	c = negVec(f, c)
 
-	d := slices.Concat(b_plus, b_negated, util.Zeros(n+m_minus))
+	d := slices.Concat(b_plus, b_negated, filled(n+m_minus, f.Zero()))
 
-	D := [][]float64{}
+	D := [][]T{}
 
 	if m_plus > 0 {
-		D_1 := util.ConcatColumns(A_plus, util.ZeroMatrix(m_plus, m_minus))
+		D_1 := util.ConcatColumns(A_plus, zeroMatrix(f, m_plus, m_minus))
 		D = slices.Concat(D, D_1)
 	}
 	if m_minus > 0 {
-		D_2 := util.ConcatColumns(A_negated, util.NegMatrix(util.IdentityMatrix(m_minus)))
+		D_2 := util.ConcatColumns(A_negated, negMatrix(f, identityMatrix(f, m_minus)))
 		D = slices.Concat(D, D_2)
 	}
 
-	D_3 := util.ConcatColumns(util.NegMatrix(util.IdentityMatrix(n)), util.ZeroMatrix(n, m_minus))
+	D_3 := util.ConcatColumns(negMatrix(f, identityMatrix(f, n)), zeroMatrix(f, n, m_minus))
 	D = slices.Concat(D, D_3)
 
 	if m_minus > 0 {
-		D_4 := util.ConcatColumns(util.ZeroMatrix(m_minus, n), util.NegMatrix(util.IdentityMatrix(m_minus)))
+		D_4 := util.ConcatColumns(zeroMatrix(f, m_minus, n), negMatrix(f, identityMatrix(f, m_minus)))
 		D = slices.Concat(D, D_4)
 	}
 
@@ -57,24 +87,64 @@ func PhaseOne(A [][]float64, b []float64, useDualSimplex bool) (basis []int, fea
 		startbasis[i] = m_plus + m_minus + i
 	}
 
-	var optimalValue float64
-	var resultbasis []int
-	if useDualSimplex {
-		err = errors.New("to be implemented correctly")
-		return
-		_, _, optimalValue, resultbasis, err = DualSimplex(util.Transpose(D), d, util.Neg(c), startbasis)
-	} else {
-		_, _, optimalValue, resultbasis, err = Simplex(c, D, d, startbasis)
+	_, y, optimalValue, resultbasis, err := SimplexOver(ctx, f, c, D, d, startbasis, opts...)
+	if err != nil {
+		return nil, false, nil, err
 	}
 
-	if err != nil {
-		return nil, false, err
+	if f.Cmp(f.Neg(optimalValue), sum(f, b_minus)) > 0 {
+		/*
+			The dual y of the auxiliary problem satisfies y@D = c. Its part for the rows of A with
+			non-negative right hand side and its part for the slack rows -s <= 0 of the other rows
+			combine the rows of A to y@A = y_(-I) >= 0 with y@b = optimalValue + sum(b_minus) < 0.
+		*/
+		farkas = filled(m, f.Zero())
+		for k, i := range pos_rows {
+			farkas[i] = y[k]
+		}
+		for k, i := range neg_rows {
+			farkas[i] = y[m+n+k]
+		}
+		return nil, false, farkas, nil
 	}
 
-	if -optimalValue > util.DotProduct(util.Ones(m_minus), b_minus) {
-		return nil, false, nil
+	return phaseOneBasis(resultbasis, m, n, pos_rows, neg_rows), true, nil, nil
+}
+
+// The dual simplex route of PhaseOneOver.
+func phaseOneDual[T any](ctx context.Context, f numbers.Field[T], A [][]T, b []T, opts ...Option) (basis []int, feasible bool, farkas []T, err error) {
+	m := len(A)
+	n := len(A[0])
+
+	system := slices.Concat(A, negMatrix(f, identityMatrix(f, n)))
+	rhs := slices.Concat(b, filled(n, f.Zero()))
+	// y = 1 on the rows of x >= 0 solves y@(-I) = -1, so x = 0 is a dual feasible basis for max -sum(x)
+	c := filled(n, f.Neg(f.One()))
+	startbasis := make([]int, n)
+	for j := range n {
+		startbasis[j] = m + j
 	}
 
+	_, _, _, basis, err = DualSimplexOver(ctx, f, system, rhs, c, startbasis, opts...)
+	var unbounded *DualUnboundedError[T]
+	if errors.As(err, &unbounded) {
+		// ray@[A; -I] = 0 and ray@[b; 0] < 0, so its part for A is a certificate
+		return nil, false, unbounded.Ray[:m], nil
+	}
+	if err != nil {
+		return nil, false, nil, err
+	}
+	return basis, true, nil, nil
+}
+
+/*
+Translates an optimal basis of the auxiliary problem of PhaseOne into a feasible basis of the system
+[A; -I]@x <= [b; 0]. The auxiliary problem has the rows of A with non-negative right hand side
+(pos_rows) first, then the other rows (neg_rows), then -I and finally one slack row for every neg_row.
+*/
+func phaseOneBasis(resultbasis []int, m int, n int, pos_rows []int, neg_rows []int) (basis []int) {
+	m_plus := len(pos_rows)
+	m_minus := len(neg_rows)
 	basis = make([]int, n)
 
 	j := 0
@@ -96,9 +166,18 @@ func PhaseOne(A [][]float64, b []float64, useDualSimplex bool) (basis []int, fea
 			}
 		}
 
-		basis[j] = variable
+		// D lists the rows with non-negative right hand side first, so translate back to the order of A
+		switch {
+		case variable < m_plus:
+			basis[j] = pos_rows[variable]
+		case variable < m:
+			basis[j] = neg_rows[variable-m_plus]
+		default:
+			basis[j] = variable
+		}
 		j++
 	}
+	slices.Sort(basis)
 
-	return basis, true, nil
+	return basis
 }
@@ -0,0 +1,91 @@
+package lp_test
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// Checks that y >= 0, y@A >= 0 and y@b < 0.
+func assertFarkas(t *testing.T, A [][]float64, b []float64, y []float64) {
+	assert.Len(t, y, len(A))
+	for _, v := range y {
+		assert.GreaterOrEqual(t, v, -epsilon)
+	}
+	for _, v := range util.MatMul(util.Transpose(A), y) {
+		assert.GreaterOrEqual(t, v, -epsilon)
+	}
+	assert.Less(t, util.DotProduct(y, b), -epsilon)
+}
+
+func TestPhaseOneInfeasible(t *testing.T) {
+	// x_1 + x_2 <= 2 contradicts x_1 + x_2 >= 3, x_1 <= 5 is not involved
+	A := [][]float64{
+		{1, 1},
+		{1, 0},
+		{-1, -1},
+	}
+	b := []float64{2, 5, -3}
+
+	for _, useDualSimplex := range []bool{false, true} {
+		basis, feasible, farkas, err := lp.PhaseOne(A, b, useDualSimplex)
+		assert.NoError(t, err)
+		assert.False(t, feasible)
+		assert.Nil(t, basis)
+		assertFarkas(t, A, b, farkas)
+	}
+
+	f := numbers.Rational{}
+	_, feasible, farkas, err := lp.PhaseOneOver(context.Background(), f, numbers.FromFloatMatrix(f, A), numbers.FromFloats(f, b), true)
+	assert.NoError(t, err)
+	assert.False(t, feasible)
+	assertFarkas(t, A, b, numbers.ToFloats(f, farkas))
+	// exactly y@b < 0
+	yb := new(big.Rat)
+	for i, v := range farkas {
+		yb.Add(yb, new(big.Rat).Mul(v, big.NewRat(int64(b[i]), 1)))
+	}
+	assert.Equal(t, -1, yb.Sign())
+}
+
+func TestPhaseOneDualSimplexMatchesPrimal(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	for range 50 {
+		m, n := 2+random.Intn(5), 1+random.Intn(4)
+		A := make([][]float64, m)
+		b := make([]float64, m)
+		for i := range m {
+			A[i] = make([]float64, n)
+			for j := range n {
+				A[i][j] = float64(random.Intn(11) - 5)
+			}
+			b[i] = float64(random.Intn(21) - 10)
+		}
+
+		_, primalFeasible, _, err := lp.PhaseOne(A, b, false)
+		assert.NoError(t, err)
+		basis, feasible, farkas, err := lp.PhaseOne(A, b, true)
+		assert.NoError(t, err)
+		assert.Equal(t, primalFeasible, feasible)
+
+		if !feasible {
+			assertFarkas(t, A, b, farkas)
+			continue
+		}
+		// the vertex of the basis satisfies the system
+		system := slices.Concat(A, util.NegMatrix(util.IdentityMatrix(n)))
+		rhs := slices.Concat(b, make([]float64, n))
+		x, err := lp.SolveLinearSystem(util.GetRows(system, basis), util.GetValues(rhs, basis))
+		assert.NoError(t, err)
+		for i, row := range system {
+			assert.LessOrEqual(t, util.DotProduct(row, x), rhs[i]+epsilon)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package lp
+
+import "math"
+
+// After this many degenerate pivots in a row, Simplex and DualSimplex fall back to Bland's rule until the objective moves again.
+const degeneratePivotsBeforeBland = 5
+
+/*
+A row that Simplex may let leave the basis, or that DualSimplex may let enter it.
+Price is how fast the objective improves along the edge the pivot leads to: -y_i for Simplex
+and the violation A_i@x - b_i of the row for DualSimplex. It is always positive.
+*/
+type PivotCandidate struct {
+	Row   int
+	Price float64
+}
+
+/*
+Decides on which of the improving rows Simplex and DualSimplex pivot, see WithPivotRule.
+
+Reset is called when a solver starts. Select gets the candidates ordered by row index and returns
+the position of the chosen one. norm(k) computes the squared length of the edge of candidates[k]
+on demand. After the pivot, Pivoted gets the row that was pivoted on, which is not the chosen one
+if the solver fell back to Bland's rule without asking Select. ratios() computes on demand the
+candidates of the next iteration that the pivot can affect, mapped to the ratio by which the pivot
+scaled their edge relative to the edge of the pivot.
+*/
+type PivotRule interface {
+	Reset()
+	Select(candidates []PivotCandidate, norm func(k int) float64) int
+	Pivoted(row int, ratios func() map[int]float64)
+}
+
+/*
+Bland's rule: the candidate with the smallest row index. It never cycles, but takes many iterations.
+This is the default, and the fallback of all other rules once degenerate pivots pile up.
+*/
+type Bland struct{}
+
+func (Bland) Reset()                                                      {}
+func (Bland) Select(candidates []PivotCandidate, _ func(int) float64) int { return 0 }
+func (Bland) Pivoted(int, func() map[int]float64)                         {}
+
+// Dantzig's rule: the candidate with the largest price.
+type Dantzig struct{}
+
+func (Dantzig) Reset() {}
+func (Dantzig) Select(candidates []PivotCandidate, _ func(int) float64) int {
+	return best(candidates, func(k int) float64 { return candidates[k].Price })
+}
+func (Dantzig) Pivoted(int, func() map[int]float64) {}
+
+/*
+The steepest edge rule: the candidate with the largest price per length of its edge,
+which takes few iterations, but computes the length of every candidate's edge in every iteration.
+*/
+type SteepestEdge struct{}
+
+func (SteepestEdge) Reset() {}
+func (SteepestEdge) Select(candidates []PivotCandidate, norm func(int) float64) int {
+	return best(candidates, func(k int) float64 { return candidates[k].Price * candidates[k].Price / norm(k) })
+}
+func (SteepestEdge) Pivoted(int, func() map[int]float64) {}
+
+/*
+Devex approximates the steepest edge rule by reference weights instead of exact edge lengths.
+All weights start at one. After a pivot on the edge with weight w_p, the weight of every edge the
+pivot scaled by ratio r grows to at least r^2 * w_p, following Forrest and Goldfarb.
+*/
+type Devex struct {
+	weights map[int]float64
+}
+
+func (d *Devex) Reset() {
+	d.weights = map[int]float64{}
+}
+
+func (d *Devex) Select(candidates []PivotCandidate, _ func(int) float64) int {
+	return best(candidates, func(k int) float64 { return candidates[k].Price * candidates[k].Price / d.weight(candidates[k].Row) })
+}
+
+func (d *Devex) Pivoted(row int, ratios func() map[int]float64) {
+	w := d.weight(row)
+	delete(d.weights, row)
+	for r, ratio := range ratios() {
+		d.weights[r] = math.Max(d.weight(r), ratio*ratio*w)
+	}
+}
+
+func (d *Devex) weight(row int) float64 {
+	if w, ok := d.weights[row]; ok {
+		return w
+	}
+	return 1
+}
+
+/*
+Partial pricing: Dantzig's rule restricted to the next Segment candidates, taking turns through
+all candidates from one iteration to the next, so that one iteration looks at a few candidates only.
+*/
+type PartialPricing struct {
+	Segment int
+	offset  int
+}
+
+func (p *PartialPricing) Reset() {
+	p.offset = 0
+}
+
+func (p *PartialPricing) Select(candidates []PivotCandidate, _ func(int) float64) int {
+	start := p.offset % len(candidates)
+	end := min(start+max(p.Segment, 1), len(candidates))
+	p.offset = end
+	return start + best(candidates[start:end], func(k int) float64 { return candidates[start+k].Price })
+}
+
+func (p *PartialPricing) Pivoted(int, func() map[int]float64) {}
+
+// The position of the candidate with the highest score, the first one in case of ties.
+func best(candidates []PivotCandidate, score func(k int) float64) int {
+	selected, highest := 0, score(0)
+	for k := 1; k < len(candidates); k++ {
+		if s := score(k); s > highest {
+			selected, highest = k, s
+		}
+	}
+	return selected
+}
+
+/*
+Chooses a pivot among the candidates by the rule, or by Bland's rule after too many degenerate pivots.
+Returns the row of the chosen candidate.
+*/
+func selectPivot(rule PivotRule, degenerate int, candidates []PivotCandidate, norm func(k int) float64) int {
+	if degenerate >= degeneratePivotsBeforeBland {
+		return candidates[0].Row
+	}
+	return candidates[rule.Select(candidates, norm)].Row
+}
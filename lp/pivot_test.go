@@ -0,0 +1,167 @@
+package lp_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func pivotRules() map[string]lp.PivotRule {
+	return map[string]lp.PivotRule{
+		"bland":           lp.Bland{},
+		"dantzig":         lp.Dantzig{},
+		"steepest edge":   lp.SteepestEdge{},
+		"devex":           &lp.Devex{},
+		"partial pricing": &lp.PartialPricing{Segment: 2},
+	}
+}
+
+func TestPivotRulesExample92(t *testing.T) {
+	A := [][]float64{
+		{110, 205, 160, 160, 420, 260},
+		{4, 32, 13, 8, 4, 14},
+		{2, 12, 54, 284, 22, 80},
+		{-110, -205, -160, -160, -420, -260},
+		{-4, -32, -13, -8, -4, -14},
+		{-2, -12, -54, -284, -22, -80},
+		{-1, 0, 0, 0, 0, 0},
+		{0, -1, 0, 0, 0, 0},
+		{0, 0, -1, 0, 0, 0},
+		{0, 0, 0, -1, 0, 0},
+		{0, 0, 0, 0, -1, 0},
+		{0, 0, 0, 0, 0, -1},
+	}
+	b := []float64{2000, 55, 800, -2000, -55, -800, 0, 0, 0, 0, 0, 0}
+	c := []float64{-3, -24, -13, -9, -20, -19}
+	startbasis := []int{0, 1, 2, 6, 7, 11}
+
+	for name, rule := range pivotRules() {
+		_, _, optimalValue, _, err := lp.Simplex(c, A, b, startbasis, lp.WithPivotRule(rule))
+		assert.NoError(t, err, name)
+		assert.InDelta(t, -59421.0/704.0, optimalValue, epsilon, name)
+	}
+}
+
+func TestPivotRulesAgree(t *testing.T) {
+	random := rand.New(rand.NewSource(17))
+	m, n := 30, 8
+
+	for range 10 {
+		// packing constraints with many rows through the origin, so x = 0 is a degenerate start
+		A := make([][]float64, 0, m+n)
+		b := make([]float64, 0, m+n)
+		for i := range m {
+			row := make([]float64, n)
+			for j := range row {
+				row[j] = float64(random.Intn(11) - 3)
+			}
+			A = append(A, row)
+			if i%3 == 0 {
+				b = append(b, 0)
+			} else {
+				b = append(b, float64(10+random.Intn(50)))
+			}
+		}
+		A = append(A, util.NegMatrix(util.IdentityMatrix(n))...)
+		b = append(b, make([]float64, n)...)
+		c := make([]float64, n)
+		for j := range c {
+			c[j] = float64(random.Intn(10))
+		}
+		startbasis := make([]int, n)
+		for j := range startbasis {
+			startbasis[j] = m + j
+		}
+
+		_, _, expected, _, expectedErr := lp.Simplex(c, A, b, startbasis)
+		for name, rule := range pivotRules() {
+			_, _, optimalValue, _, err := lp.Simplex(c, A, b, startbasis, lp.WithPivotRule(rule))
+			assert.Equal(t, expectedErr, err, name)
+			if expectedErr == nil {
+				assert.InDelta(t, expected, optimalValue, 1e-6, name)
+			}
+		}
+
+		// the dual simplex on the same rows with the objective -c, for which x = 0 is dual feasible
+		negated := util.Neg(c)
+		_, _, expected, _, expectedErr = lp.DualSimplex(A, b, negated, startbasis)
+		for name, rule := range pivotRules() {
+			_, _, optimalValue, _, err := lp.DualSimplex(A, b, negated, startbasis, lp.WithPivotRule(rule))
+			assert.Equal(t, expectedErr, err, name)
+			if expectedErr == nil {
+				assert.InDelta(t, expected, optimalValue, 1e-6, name)
+			}
+		}
+	}
+}
+
+func TestPivotRulesOnKleeMintyCube(t *testing.T) {
+	// a deformed cube on which Dantzig's rule visits most of the vertices, while the steepest edge does not
+	n := 6
+	A := [][]float64{}
+	b := []float64{}
+	for i := range n {
+		row := make([]float64, n)
+		for j := range i {
+			row[j] = 2 * float64(int(1)<<(i-j))
+		}
+		row[i] = 1
+		A = append(A, row)
+		b = append(b, float64(int(1)<<(2*i)))
+	}
+	A = append(A, util.NegMatrix(util.IdentityMatrix(n))...)
+	b = append(b, make([]float64, n)...)
+	c := make([]float64, n)
+	for j := range c {
+		c[j] = float64(int(1) << (n - 1 - j))
+	}
+	startbasis := make([]int, n)
+	for j := range startbasis {
+		startbasis[j] = n + j
+	}
+
+	iterations := map[string]int{}
+	for name, rule := range pivotRules() {
+		recorder := &iterationCounter{}
+		_, _, optimalValue, _, err := lp.Simplex(c, A, b, startbasis, lp.WithPivotRule(rule), lp.WithObserver(recorder))
+		assert.NoError(t, err, name)
+		assert.InDelta(t, float64(int(1)<<(2*(n-1))), optimalValue, epsilon, name)
+		iterations[name] = recorder.iterations
+	}
+	assert.Greater(t, iterations["dantzig"], 2*iterations["steepest edge"])
+	assert.Greater(t, iterations["dantzig"], 2*iterations["devex"])
+	assert.LessOrEqual(t, iterations["steepest edge"], iterations["bland"])
+}
+
+type iterationCounter struct {
+	iterations int
+}
+
+func (c *iterationCounter) Observe(e trace.Event) {
+	if _, ok := e.(lp.SimplexIteration); ok {
+		c.iterations++
+	}
+}
+
+func TestDevexWeightsFollowThePivotedRow(t *testing.T) {
+	var devex lp.Devex
+	devex.Reset()
+	ratios := func(ratios map[int]float64) func() map[int]float64 {
+		return func() map[int]float64 { return ratios }
+	}
+
+	k := devex.Select([]lp.PivotCandidate{{Row: 1, Price: 2}, {Row: 2, Price: 1}}, nil)
+	assert.Equal(t, 0, k)
+	// row 1 pivots and scales the edge of row 2 by 3, so its weight becomes 9
+	devex.Pivoted(1, ratios(map[int]float64{2: 3}))
+	// after degenerate pivots, Bland's rule pivots on row 2 without asking Select, which passes its weight on to row 4
+	devex.Pivoted(2, ratios(map[int]float64{4: 1}))
+
+	// row 2 is back to weight 1 and row 4 has weight 9
+	k = devex.Select([]lp.PivotCandidate{{Row: 2, Price: 2}, {Row: 4, Price: 2.5}}, nil)
+	assert.Equal(t, 0, k)
+}
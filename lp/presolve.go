@@ -0,0 +1,443 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+// How much tighter relative to its size an implied bound has to be to replace the current one.
+const boundTighteningThreshold = 1e-3
+
+/*
+The result of Presolve: the reduced program max C@x' s.t. A@x' <= B, Lower <= x' <= Upper in the
+variables that presolve could not fix, together with what Postsolve needs to map a solution of it
+back to max c@x s.t. A@x <= b, x >= 0. Rows and Columns are the rows and columns of the original
+system that the reduced one keeps, in the same order.
+The reduced system is scaled, so its coefficients are not those of the original rows and columns.
+*/
+type Presolved struct {
+	C       []float64
+	A       [][]float64
+	B       []float64
+	Lower   []float64
+	Upper   []float64
+	Rows    []int
+	Columns []int
+
+	c           []float64
+	a           [][]float64
+	opts        []Option
+	rowScale    []float64
+	columnScale []float64
+	steps       []postsolveStep
+}
+
+/*
+One reduction of Presolve, undone by Postsolve in reverse order. A removed row has column -1,
+a fixed column row -1 and a bound of column from a singleton row or implied by a row both.
+*/
+type postsolveStep struct {
+	row         int
+	column      int
+	value       float64
+	coefficient float64
+	bound       float64
+	upper       bool
+	tightened   bool
+}
+
+/*
+Reduces max c@x s.t. A@x <= b, x >= 0 before solving it, see Presolved. The reductions are repeated
+until none of them applies any more:
+
+  - Empty rows are dropped, or prove the system infeasible.
+  - Singleton rows a_ij*x_j <= b_i become bounds of x_j.
+  - Variables whose bounds meet are fixed and moved to the right hand side.
+  - Variables that cannot make a row tighter without lowering the objective are fixed at the
+    bound the objective prefers. This includes variables that no row uses any more.
+  - Rows that hold for all x within the bounds are dropped, rows that hold for none of them
+    prove the system infeasible.
+  - The other rows imply bounds on their variables: a_ij*x_j is at most b_i minus the smallest
+    value of the rest of the row within the bounds. Such a bound replaces the one of x_j if it
+    is tighter by a relevant amount, so that the bounds do not creep towards their limit forever.
+  - Of two rows that are positive multiples of each other, the looser one is dropped.
+
+Finally the rows and columns of the rest are scaled by powers of two to bring their coefficients
+closer to one, which does not introduce rounding errors. If presolve finds the system infeasible,
+ErrInfeasible is returned. Unboundedness is left to the solver.
+*/
+func Presolve(c []float64, A [][]float64, b []float64, opts ...Option) (*Presolved, error) {
+	if len(A) == 0 || len(b) != len(A) {
+		return nil, ErrInvalidInput
+	}
+	m, n := len(A), len(c)
+	for _, row := range A {
+		if len(row) != n {
+			return nil, ErrInvalidInput
+		}
+	}
+	tol := collectOptions(opts).tolerance
+
+	p := &Presolved{c: slices.Clone(c), opts: opts}
+	p.a = make([][]float64, m)
+	for i, row := range A {
+		p.a[i] = slices.Clone(row)
+	}
+	rhs := slices.Clone(b)
+	lower := make([]float64, n)
+	upper := make([]float64, n)
+	for j := range upper {
+		upper[j] = math.Inf(1)
+	}
+	rowActive := make([]bool, m)
+	for i := range rowActive {
+		rowActive[i] = true
+	}
+	columnActive := make([]bool, n)
+	for j := range columnActive {
+		columnActive[j] = true
+	}
+
+	// the active columns with a non-zero coefficient in row i
+	support := func(i int) (s []int) {
+		for j, v := range A[i] {
+			if columnActive[j] && !tol.IsZero(v) {
+				s = append(s, j)
+			}
+		}
+		return s
+	}
+	removeRow := func(i int) {
+		rowActive[i] = false
+		p.steps = append(p.steps, postsolveStep{row: i, column: -1})
+	}
+	fix := func(j int, value float64) {
+		for i := range m {
+			if rowActive[i] {
+				rhs[i] -= A[i][j] * value
+			}
+		}
+		columnActive[j] = false
+		p.steps = append(p.steps, postsolveStep{row: -1, column: j, value: value})
+	}
+	// the smallest and the largest value of A_i@x within the bounds
+	activity := func(i int, s []int) (lowest float64, highest float64) {
+		for _, j := range s {
+			if A[i][j] > 0 {
+				lowest += A[i][j] * lower[j]
+				highest += A[i][j] * upper[j]
+			} else {
+				lowest += A[i][j] * upper[j]
+				highest += A[i][j] * lower[j]
+			}
+		}
+		return lowest, highest
+	}
+
+	/*
+		Tightens the bounds of the columns s of row i by the bounds the row implies and returns
+		whether it changed one. The smallest value of the rest of the row is only finite if every
+		other column that could make it infinite is bounded.
+	*/
+	tighten := func(i int, s []int) (tightened bool) {
+		finite, infinite := 0.0, 0
+		contribution := func(j int) float64 {
+			if A[i][j] > 0 {
+				return A[i][j] * lower[j]
+			}
+			return A[i][j] * upper[j]
+		}
+		for _, j := range s {
+			if v := contribution(j); math.IsInf(v, -1) {
+				infinite++
+			} else {
+				finite += v
+			}
+		}
+		for _, j := range s {
+			rest := finite - contribution(j)
+			if math.IsInf(contribution(j), -1) {
+				rest = finite
+				if infinite > 1 {
+					continue
+				}
+			} else if infinite > 0 {
+				continue
+			}
+			step := postsolveStep{row: i, column: j, coefficient: A[i][j], bound: (rhs[i] - rest) / A[i][j], upper: A[i][j] > 0, tightened: true}
+			threshold := boundTighteningThreshold * max(1, math.Abs(step.bound))
+			if step.upper && step.bound < upper[j]-threshold {
+				upper[j] = step.bound
+			} else if !step.upper && step.bound > lower[j]+threshold {
+				lower[j] = step.bound
+			} else {
+				continue
+			}
+			p.steps = append(p.steps, step)
+			tightened = true
+		}
+		return tightened
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for i := range m {
+			if !rowActive[i] {
+				continue
+			}
+			s := support(i)
+			switch len(s) {
+			case 0:
+				if tol.Sign(rhs[i]) < 0 {
+					return nil, ErrInfeasible
+				}
+				removeRow(i)
+			case 1:
+				j := s[0]
+				step := postsolveStep{row: i, column: j, coefficient: A[i][j], bound: rhs[i] / A[i][j], upper: A[i][j] > 0}
+				if step.upper && tol.Less(step.bound, upper[j]) {
+					upper[j], step.tightened = step.bound, true
+				}
+				if !step.upper && tol.Greater(step.bound, lower[j]) {
+					lower[j], step.tightened = step.bound, true
+				}
+				rowActive[i] = false
+				p.steps = append(p.steps, step)
+			default:
+				lowest, highest := activity(i, s)
+				if tol.Greater(lowest, rhs[i]) {
+					return nil, ErrInfeasible
+				}
+				if !tol.Greater(highest, rhs[i]) {
+					removeRow(i)
+				} else if !tighten(i, s) {
+					continue
+				}
+			}
+			changed = true
+		}
+
+		for j := range n {
+			if !columnActive[j] {
+				continue
+			}
+			if tol.Greater(lower[j], upper[j]) {
+				return nil, ErrInfeasible
+			}
+			if tol.Equal(lower[j], upper[j]) {
+				fix(j, lower[j])
+				changed = true
+				continue
+			}
+			nonNegative, nonPositive := true, true
+			for i := range m {
+				if rowActive[i] {
+					nonNegative = nonNegative && tol.Sign(A[i][j]) >= 0
+					nonPositive = nonPositive && tol.Sign(A[i][j]) <= 0
+				}
+			}
+			switch {
+			case nonNegative && tol.Sign(c[j]) <= 0:
+				fix(j, lower[j])
+			case nonPositive && tol.Sign(c[j]) >= 0 && !math.IsInf(upper[j], 1):
+				fix(j, upper[j])
+			default:
+				continue
+			}
+			changed = true
+		}
+
+		for i := range m {
+			for k := i + 1; k < m && rowActive[i]; k++ {
+				if !rowActive[k] {
+					continue
+				}
+				factor, ok := parallel(A[i], A[k], columnActive, tol)
+				if !ok {
+					continue
+				}
+				// A_k = factor*A_i, so row k states A_i@x <= b_k/factor
+				if tol.Less(rhs[k]/factor, rhs[i]) {
+					removeRow(i)
+				} else {
+					removeRow(k)
+				}
+				changed = true
+			}
+		}
+	}
+
+	for i := range m {
+		if rowActive[i] {
+			p.Rows = append(p.Rows, i)
+		}
+	}
+	for j := range n {
+		if columnActive[j] {
+			p.Columns = append(p.Columns, j)
+		}
+	}
+	p.rowScale, p.columnScale = scaling(A, p.Rows, p.Columns)
+
+	p.A = make([][]float64, len(p.Rows))
+	p.B = make([]float64, len(p.Rows))
+	for k, i := range p.Rows {
+		p.A[k] = make([]float64, len(p.Columns))
+		for l, j := range p.Columns {
+			p.A[k][l] = p.rowScale[k] * A[i][j] * p.columnScale[l]
+		}
+		p.B[k] = p.rowScale[k] * rhs[i]
+	}
+	for l, j := range p.Columns {
+		p.C = append(p.C, c[j]*p.columnScale[l])
+		p.Lower = append(p.Lower, lower[j]/p.columnScale[l])
+		p.Upper = append(p.Upper, upper[j]/p.columnScale[l])
+	}
+	return p, nil
+}
+
+// The positive factor with A_k = factor*A_i on the active columns, if there is one and the rows have more than one entry.
+func parallel(row []float64, other []float64, columnActive []bool, tol util.Tolerance) (factor float64, ok bool) {
+	entries := 0
+	for j := range row {
+		if !columnActive[j] || tol.IsZero(row[j]) && tol.IsZero(other[j]) {
+			continue
+		}
+		if tol.IsZero(row[j]) || tol.IsZero(other[j]) {
+			return 0, false
+		}
+		if entries == 0 {
+			factor = other[j] / row[j]
+		}
+		if factor <= 0 || !tol.Equal(other[j], factor*row[j]) {
+			return 0, false
+		}
+		entries++
+	}
+	return factor, entries > 1
+}
+
+/*
+Geometric mean scaling: every row and then every column is multiplied by the power of two closest
+to one over the geometric mean of its smallest and largest absolute coefficient.
+*/
+func scaling(A [][]float64, rows []int, columns []int) (rowScale []float64, columnScale []float64) {
+	factor := func(values []float64) float64 {
+		smallest, largest := math.Inf(1), 0.0
+		for _, v := range values {
+			if v != 0 {
+				smallest = min(smallest, math.Abs(v))
+				largest = max(largest, math.Abs(v))
+			}
+		}
+		if largest == 0 {
+			return 1
+		}
+		return math.Exp2(-math.Round(math.Log2(math.Sqrt(smallest * largest))))
+	}
+
+	rowScale = make([]float64, len(rows))
+	for k, i := range rows {
+		rowScale[k] = factor(util.GetValues(A[i], columns))
+	}
+	columnScale = make([]float64, len(columns))
+	for l, j := range columns {
+		column := make([]float64, len(rows))
+		for k, i := range rows {
+			column[k] = rowScale[k] * A[i][j]
+		}
+		columnScale[l] = factor(column)
+	}
+	return rowScale, columnScale
+}
+
+/*
+Maps a primal solution x and dual solution y of the reduced program back to the original one.
+Fixed variables get their values, removed rows a dual value of zero, and a singleton row whose bound
+holds x_j takes over the reduced cost of x_j as its dual value, so an optimal pair of the reduced
+program becomes an optimal pair x >= 0, y >= 0 with y@A >= c of the original one. The same goes
+for a row that implied the bound holding x_j: as x_j is at that bound, the row is tight and its
+other columns are at the bounds that the implication used, whose reduced costs keep the right sign
+when the row's dual value grows.
+*/
+func (p *Presolved) Postsolve(x []float64, y []float64) (originalX []float64, originalY []float64) {
+	tol := collectOptions(p.opts).tolerance
+	originalX = make([]float64, len(p.c))
+	originalY = make([]float64, len(p.a))
+	for l, j := range p.Columns {
+		originalX[j] = p.columnScale[l] * x[l]
+	}
+	for k, i := range p.Rows {
+		if y != nil {
+			originalY[i] = p.rowScale[k] * y[k]
+		}
+	}
+
+	for s := len(p.steps) - 1; s >= 0; s-- {
+		step := p.steps[s]
+		switch {
+		case step.row == -1:
+			originalX[step.column] = step.value
+		case step.column == -1 || !step.tightened:
+			// the row does not hold x, so its dual value stays zero
+		default:
+			j := step.column
+			reduced := p.c[j] - util.DotProduct(originalY, util.GetColumn(p.a, j))
+			atBound := tol.Equal(originalX[j], step.bound)
+			if atBound && (step.upper && tol.Sign(reduced) > 0 || !step.upper && tol.Sign(reduced) < 0) {
+				originalY[step.row] += reduced / step.coefficient
+			}
+		}
+	}
+	return originalX, originalY
+}
+
+/*
+Solves the reduced program with BoundedSimplex and returns the postsolved solution of the original
+one, see SolveContext.
+*/
+func (p *Presolved) Solve() (x []float64, y []float64, optimalValue float64, err error) {
+	return p.SolveContext(context.Background())
+}
+
+/*
+Solves the reduced program with BoundedSimplexContext and returns the postsolved solution of the
+original one. If the program is unbounded, the error is an *UnboundedError[float64] in the original variables.
+*/
+func (p *Presolved) SolveContext(ctx context.Context) (x []float64, y []float64, optimalValue float64, err error) {
+	var reducedX, reducedY []float64
+	if len(p.A) == 0 {
+		/*
+			No rows are left, so presolve fixed every variable except those that the objective
+			drives towards an infinite upper bound.
+		*/
+		reducedX = slices.Clone(p.Lower)
+		if len(p.C) > 0 {
+			ray := make([]float64, len(p.C))
+			ray[0] = 1
+			err = &UnboundedError[float64]{X: reducedX, Ray: ray}
+		}
+	} else {
+		reducedX, reducedY, _, err = BoundedSimplexContext(ctx, p.C, p.A, p.B, p.Lower, p.Upper, p.opts...)
+	}
+
+	var unbounded *UnboundedError[float64]
+	if errors.As(err, &unbounded) {
+		x, _ = p.Postsolve(unbounded.X, nil)
+		ray := make([]float64, len(p.c))
+		for l, j := range p.Columns {
+			ray[j] = p.columnScale[l] * unbounded.Ray[l]
+		}
+		return nil, nil, 0, &UnboundedError[float64]{X: x, Ray: ray}
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	x, y = p.Postsolve(reducedX, reducedY)
+	return x, y, util.DotProduct(p.c, x), nil
+}
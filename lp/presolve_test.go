@@ -0,0 +1,126 @@
+package lp_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// Checks that y is an optimal dual solution to x for max c@x s.t. A@x <= b, x >= 0.
+func assertOptimalPair(t *testing.T, c []float64, A [][]float64, b []float64, x []float64, y []float64) {
+	for i, row := range A {
+		assert.GreaterOrEqual(t, y[i], -1e-6)
+		assert.LessOrEqual(t, util.DotProduct(row, x), b[i]+1e-6)
+	}
+	yA := util.MatMul(util.Transpose(A), y)
+	for j := range c {
+		assert.GreaterOrEqual(t, x[j], -1e-6)
+		assert.GreaterOrEqual(t, yA[j], c[j]-1e-6)
+	}
+	assert.InDelta(t, util.DotProduct(c, x), util.DotProduct(y, b), 1e-6)
+}
+
+func TestPresolve(t *testing.T) {
+	c := []float64{3, 2, -1, 1}
+	A := [][]float64{
+		{1, 1, 1, 0},
+		{0, 0, 0, 0}, // empty
+		{2, 0, 0, 0}, // x_0 <= 2
+		{2, 2, 2, 0}, // twice row 0, but looser
+		{1, 1, 0, 0},
+		{0, 0, 0, -1}, // x_3 >= 1
+		{0, 0, 0, 1},  // x_3 <= 1
+	}
+	b := []float64{4, 3, 4, 10, 5, -1, 1}
+
+	p, err := lp.Presolve(c, A, b)
+	assert.NoError(t, err)
+	// x_2 only hurts, x_3 is fixed, then row 4 is dominated by x_0 <= 2 and row 0
+	assert.Equal(t, []int{0}, p.Rows)
+	assert.Equal(t, []int{0, 1}, p.Columns)
+
+	x, y, optimalValue, err := p.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{2, 2, 0, 1}, x, epsilon)
+	assert.InDelta(t, 11, optimalValue, epsilon)
+	assertOptimalPair(t, c, A, b, x, y)
+
+	// x_0 >= 3 contradicts 2*x_0 <= 4
+	_, err = lp.Presolve([]float64{1}, [][]float64{{2}, {-1}}, []float64{4, -3})
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	_, err = lp.Presolve([]float64{1}, [][]float64{{0}}, []float64{-1})
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+
+	// nothing is left but x_0 <= 1 and an unbounded x_1
+	_, _, err = lp.Maximize([]float64{1, 1}, [][]float64{{1, 0}}, []float64{1}, lp.WithPresolve())
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+}
+
+func TestPresolveTightensBounds(t *testing.T) {
+	c := []float64{1, 1}
+	A := [][]float64{
+		{1, 1}, // x_0 <= 4, x_1 <= 4
+		{1, 2}, // x_1 <= 3
+	}
+	b := []float64{4, 6}
+
+	p, err := lp.Presolve(c, A, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, p.Rows)
+	assert.InDeltaSlice(t, []float64{4, 3}, p.Upper, epsilon)
+
+	x, y, optimalValue, err := p.Solve()
+	assert.NoError(t, err)
+	assert.InDelta(t, 4, optimalValue, epsilon)
+	assertOptimalPair(t, c, A, b, x, y)
+}
+
+func TestPresolveAgreesWithMaximize(t *testing.T) {
+	random := rand.New(rand.NewSource(43))
+	for range 50 {
+		m, n := 2+random.Intn(8), 1+random.Intn(6)
+		c, A, b := randomLP(random, m, n, 4)
+		// sparse rows, so that singletons, empty rows and fixed variables come up
+		for i := range m {
+			for j := range n {
+				if random.Intn(3) != 0 {
+					A[i][j] = 0
+				}
+			}
+		}
+		if random.Intn(2) == 0 {
+			// a duplicate row
+			A = append(A, util.ScalarMult(2, A[0]))
+			b = append(b, 2*b[0]+float64(random.Intn(3)))
+		}
+		// bound every variable, so that the program is bounded
+		for j := range n {
+			row := make([]float64, n)
+			row[j] = 1
+			A = append(A, row)
+			b = append(b, float64(1+random.Intn(10)))
+		}
+
+		_, expected, expectedErr := lp.Maximize(c, A, b)
+
+		p, err := lp.Presolve(c, A, b)
+		if err == nil {
+			assert.LessOrEqual(t, len(p.Rows), len(A))
+			var x, y []float64
+			x, y, _, err = p.Solve()
+			if err == nil {
+				assertOptimalPair(t, c, A, b, x, y)
+			}
+		}
+		assert.Equal(t, expectedErr, err)
+
+		_, optimalValue, err := lp.Maximize(c, A, b, lp.WithPresolve())
+		assert.Equal(t, expectedErr, err)
+		if expectedErr == nil {
+			assert.InDelta(t, expected, optimalValue, 1e-6)
+		}
+	}
+}
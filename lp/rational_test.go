@@ -0,0 +1,98 @@
+package lp_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/stretchr/testify/assert"
+)
+
+func rats(values ...string) []*big.Rat {
+	result := make([]*big.Rat, len(values))
+	for i, v := range values {
+		result[i], _ = new(big.Rat).SetString(v)
+	}
+	return result
+}
+
+func assertRatsEqual(t *testing.T, expected []*big.Rat, actual []*big.Rat) {
+	t.Helper()
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+	for i := range expected {
+		assert.Zero(t, expected[i].Cmp(actual[i]), "entry %d: expected %v, got %v", i, expected[i].RatString(), actual[i].RatString())
+	}
+}
+
+func TestGaussEliminationExact(t *testing.T) {
+	f := numbers.Rational{}
+	A := numbers.FromFloatMatrix(f, [][]float64{
+		{2, 1, -1},
+		{1, -1, -1},
+		{2, 2, 1},
+	})
+	b := numbers.FromFloats(f, []float64{1, 3, 1})
+
+	RU, bU, err := lp.GaussEliminationOver(f, A, b)
+	assert.NoError(t, err)
+	assertRatsEqual(t, rats("0", "-3/2", "-1/2"), RU[1])
+	assertRatsEqual(t, rats("0", "0", "5/3"), RU[2])
+	assertRatsEqual(t, rats("1", "5/2", "5/3"), bU)
+
+	x, err := lp.SolveLinearSystemOver(f, A, b)
+	assert.NoError(t, err)
+	assertRatsEqual(t, rats("2", "-2", "1"), x)
+}
+
+// With exact float64 comparisons this degenerate exercise runs into a singular basis.
+func TestSimplexExample92Exact(t *testing.T) {
+	f := numbers.Rational{}
+	A := numbers.FromFloatMatrix(f, [][]float64{
+		{110, 205, 160, 160, 420, 260},
+		{4, 32, 13, 8, 4, 14},
+		{2, 12, 54, 284, 22, 80},
+		{-110, -205, -160, -160, -420, -260},
+		{-4, -32, -13, -8, -4, -14},
+		{-2, -12, -54, -284, -22, -80},
+		{-1, 0, 0, 0, 0, 0},
+		{0, -1, 0, 0, 0, 0},
+		{0, 0, -1, 0, 0, 0},
+		{0, 0, 0, -1, 0, 0},
+		{0, 0, 0, 0, -1, 0},
+		{0, 0, 0, 0, 0, -1},
+	})
+	b := numbers.FromFloats(f, []float64{2000, 55, 800, -2000, -55, -800, 0, 0, 0, 0, 0, 0})
+	c := numbers.FromFloats(f, []float64{-3, -24, -13, -9, -20, -19})
+	startbasis := []int{0, 1, 2, 6, 7, 11}
+
+	x, y, optimalValue, basis, err := lp.SimplexOver(context.Background(), f, c, A, b, startbasis)
+	assert.NoError(t, err)
+
+	assertRatsEqual(t, rats("-59421/704"), []*big.Rat{optimalValue})
+	assertRatsEqual(t, rats("5677/880", "0", "0", "9189/3520", "3657/1760", "0"), x)
+	assertRatsEqual(t, rats("0", "2569/3520", "0", "47/880", "0", "39/1760", "0", "31803/880", "8973/704", "0", "0", "23863/1760"), y)
+	assert.Equal(t, []int{1, 3, 5, 7, 8, 11}, basis)
+}
+
+func TestDualSimplexOnH82Exact(t *testing.T) {
+	f := numbers.Rational{}
+	A := numbers.FromFloatMatrix(f, [][]float64{
+		{-1, -1},
+		{-3, 1},
+		{-1, 0},
+		{0, -1},
+	})
+	b := numbers.FromFloats(f, []float64{-2, -5, 0, 0})
+	c := numbers.FromFloats(f, []float64{-3, -1})
+
+	x, y, optimalValue, endbasis, err := lp.DualSimplexOver(context.Background(), f, A, b, c, []int{2, 3})
+	assert.NoError(t, err)
+	assertRatsEqual(t, rats("7/4", "1/4"), x)
+	assertRatsEqual(t, rats("3/2", "1/2", "0", "0"), y)
+	assertRatsEqual(t, rats("-11/2"), []*big.Rat{optimalValue})
+	assert.Equal(t, []int{0, 1}, endbasis)
+}
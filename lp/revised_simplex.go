@@ -0,0 +1,245 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+const defaultRefactorEvery = 50
+
+/*
+The basis matrix B = A_B^T in product form B = B_0@E_1@...@E_t, where B_0 is LU factorized and
+every E_k is an eta matrix, that is the identity with one column replaced.
+Replacing the column at position p of B by a then only appends the eta matrix with column B^-1@a at p,
+so a pivot costs O(n^2) instead of the O(n^3) of a fresh Gauss elimination.
+*/
+type basisFactorization[T any] struct {
+	f    numbers.Field[T]
+	lu   *LU[T]
+	etas []eta[T]
+}
+
+type eta[T any] struct {
+	position int
+	column   []T
+}
+
+func factorizeBasis[T any](f numbers.Field[T], A rowMatrix[T], basis []int) (*basisFactorization[T], error) {
+	A_B := make([][]T, len(basis))
+	for k, i := range basis {
+		A_B[k] = A.row(i)
+	}
+	lu, err := FactorizeLUOver(f, util.Transpose(A_B))
+	if err != nil {
+		return nil, err
+	}
+	return &basisFactorization[T]{f: f, lu: lu}, nil
+}
+
+// Solves B@x = v (FTRAN).
+func (b *basisFactorization[T]) ftran(v []T) []T {
+	f := b.f
+	x := b.lu.Solve(v)
+	for _, e := range b.etas {
+		p := e.position
+		x[p] = f.Div(x[p], e.column[p])
+		for i := range x {
+			if i != p {
+				x[i] = f.Sub(x[i], f.Mul(e.column[i], x[p]))
+			}
+		}
+	}
+	return x
+}
+
+// Solves B^T@x = v (BTRAN).
+func (b *basisFactorization[T]) btran(v []T) []T {
+	f := b.f
+	u := slices.Clone(v)
+	for k := len(b.etas) - 1; k >= 0; k-- {
+		e := b.etas[k]
+		p := e.position
+		s := u[p]
+		for i := range u {
+			if i != p {
+				s = f.Sub(s, f.Mul(e.column[i], u[i]))
+			}
+		}
+		u[p] = f.Div(s, e.column[p])
+	}
+	return b.lu.SolveTransposed(u)
+}
+
+// Replaces the column at the given position of B by a.
+func (b *basisFactorization[T]) update(position int, a []T) {
+	b.etas = append(b.etas, eta[T]{position: position, column: b.ftran(a)})
+}
+
+/*
+Revised simplex method on a system in natural form max c@x s.t. A@x <= b using Bland's pivot rule.
+It performs the same pivots as Simplex, but instead of inverting A_B in every iteration it keeps an LU
+factorization of the basis that is updated in product form and computed anew after every
+WithRefactorization pivots. This makes it the method of choice for systems with many rows.
+*/
+func RevisedSimplex(c []float64, A [][]float64, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	return RevisedSimplexContext(context.Background(), c, A, b, startbasis, opts...)
+}
+
+// Same as RevisedSimplex, but stops as soon as ctx is done, see SimplexContext.
+func RevisedSimplexContext(ctx context.Context, c []float64, A [][]float64, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	x, y, optimalValue, endbasis, err = RevisedSimplexOver(ctx, collectOptions(opts).float64Field(), c, A, b, startbasis, opts...)
+	if errors.Is(err, ErrUnbounded) {
+		optimalValue = math.Inf(1)
+	}
+	return
+}
+
+// Same as RevisedSimplexContext, but in the arithmetic of an arbitrary field.
+func RevisedSimplexOver[T any](ctx context.Context, f numbers.Field[T], c []T, A [][]T, b []T, startbasis []int, opts ...Option) (x []T, y []T, optimalValue T, endbasis []int, err error) {
+	return revisedSimplex(ctx, f, c, denseRows[T]{f: f, A: A}, b, startbasis, opts...)
+}
+
+// The access to the rows of A the revised simplex needs, so that A can be stored densely or sparsely.
+type rowMatrix[T any] interface {
+	rows() int
+	// row i as dense vector
+	row(i int) []T
+	rowDot(i int, v []T) T
+	mulVec(v []T) []T
+}
+
+type denseRows[T any] struct {
+	f numbers.Field[T]
+	A [][]T
+}
+
+func (d denseRows[T]) rows() int             { return len(d.A) }
+func (d denseRows[T]) row(i int) []T         { return d.A[i] }
+func (d denseRows[T]) rowDot(i int, v []T) T { return dot(d.f, d.A[i], v) }
+func (d denseRows[T]) mulVec(v []T) []T      { return matVec(d.f, d.A, v) }
+
+func revisedSimplex[T any](ctx context.Context, f numbers.Field[T], c []T, A rowMatrix[T], b []T, startbasis []int, opts ...Option) (x []T, y []T, optimalValue T, endbasis []int, err error) {
+	o := collectOptions(opts)
+
+	// in contrast to Simplex the basis is not kept sorted, since position k belongs to column k of the factorization
+	basis := slices.Clone(startbasis)
+	sorted := func() []int {
+		s := slices.Clone(basis)
+		slices.Sort(s)
+		return s
+	}
+
+	factorization, err := factorizeBasis(f, A, basis)
+	if err != nil {
+		return nil, nil, f.Zero(), nil, err
+	}
+	x = factorization.btran(util.GetValues(b, basis))
+	yValues := filled(A.rows(), f.Zero())
+
+	iter := 0
+	finish := func(status Status, objective T) {
+		o.observe(SolverFinished{Solver: "revised simplex", Iterations: iter, Status: status, Objective: f.Float(objective)})
+	}
+
+	for {
+		iter++
+
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted, dot(f, c, x))
+			return x, yValues, dot(f, c, x), sorted(), err
+		}
+		if o.exhausted(iter) {
+			finish(StatusMaxIterations, dot(f, c, x))
+			return x, yValues, dot(f, c, x), sorted(), ErrMaxIterationsReached
+		}
+
+		for i := range yValues {
+			yValues[i] = f.Zero()
+		}
+		y_B := factorization.ftran(c)
+		for k, bk := range basis {
+			yValues[bk] = y_B[k]
+		}
+
+		event := SimplexIteration{Leaving: -1, Entering: -1}
+		if o.tracing() {
+			event.Iteration = iter
+			event.X = numbers.ToFloats(f, x)
+			event.Basis = sorted()
+			event.Objective = f.Float(dot(f, c, x))
+			event.BasisY = numbers.ToFloats(f, util.GetValues(yValues, event.Basis))
+		}
+
+		// Bland's exiting rule
+		i := util.Find(yValues, func(v T) bool { return f.Sign(v) < 0 })
+
+		if i == -1 {
+			o.observe(event)
+			finish(StatusOptimal, dot(f, c, x))
+			return x, yValues, dot(f, c, x), sorted(), nil
+		}
+		event.Leaving = i
+		k := slices.Index(basis, i)
+
+		// w is the negated k-th column of A_B^-1
+		e_k := filled(len(basis), f.Zero())
+		e_k[k] = f.Neg(f.One())
+		w := factorization.btran(e_k)
+
+		ratioTest := A.mulVec(w)
+
+		jSelect := util.FindAll(ratioTest, func(v T) bool { return f.Sign(v) > 0 })
+		if o.tracing() {
+			event.Direction = numbers.ToFloats(f, w)
+			event.Slopes = numbers.ToFloats(f, ratioTest)
+			event.Candidates = jSelect
+		}
+		if len(jSelect) == 0 {
+			o.observe(event)
+			finish(StatusUnbounded, f.Zero())
+			return nil, nil, f.Zero(), sorted(), &UnboundedError[T]{X: x, Ray: w}
+		}
+
+		// Bland's entering rule
+		var gamma T
+		j := -1
+		for _, jCandidate := range jSelect {
+			if slices.Contains(basis, jCandidate) {
+				continue
+			}
+			ratio := f.Div(f.Sub(b[jCandidate], A.rowDot(jCandidate, x)), ratioTest[jCandidate])
+
+			if j == -1 || f.Cmp(ratio, gamma) < 0 {
+				gamma = ratio
+				j = jCandidate
+			}
+		}
+
+		event.Entering = j
+		if o.tracing() {
+			event.StepLength = f.Float(gamma)
+		}
+		o.observe(event)
+
+		x = addVec(f, x, scale(f, gamma, w))
+
+		if len(factorization.etas)+1 >= o.refactorEvery {
+			basis[k] = j
+			factorization, err = factorizeBasis(f, A, basis)
+			if err != nil {
+				finish(StatusFailed, dot(f, c, x))
+				return nil, nil, f.Zero(), sorted(), err
+			}
+			// recompute the vertex to get rid of the rounding errors of the updates
+			x = factorization.btran(util.GetValues(b, basis))
+		} else {
+			factorization.update(k, A.row(j))
+			basis[k] = j
+		}
+	}
+}
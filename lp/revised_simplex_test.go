@@ -0,0 +1,87 @@
+package lp_test
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisedSimplexExample92(t *testing.T) {
+	A := [][]float64{
+		{110, 205, 160, 160, 420, 260},
+		{4, 32, 13, 8, 4, 14},
+		{2, 12, 54, 284, 22, 80},
+		{-110, -205, -160, -160, -420, -260},
+		{-4, -32, -13, -8, -4, -14},
+		{-2, -12, -54, -284, -22, -80},
+		{-1, 0, 0, 0, 0, 0},
+		{0, -1, 0, 0, 0, 0},
+		{0, 0, -1, 0, 0, 0},
+		{0, 0, 0, -1, 0, 0},
+		{0, 0, 0, 0, -1, 0},
+		{0, 0, 0, 0, 0, -1},
+	}
+	b := []float64{2000, 55, 800, -2000, -55, -800, 0, 0, 0, 0, 0, 0}
+	c := []float64{-3, -24, -13, -9, -20, -19}
+	startbasis := []int{0, 1, 2, 6, 7, 11}
+
+	for _, every := range []int{1, 2, 50} {
+		x, _, optimalValue, basis, err := lp.RevisedSimplex(c, A, b, startbasis, lp.WithRefactorization(every))
+		assert.NoError(t, err)
+		assert.InDelta(t, -59421.0/704.0, optimalValue, epsilon)
+		expectedX := []float64{5677.0 / 880.0, 0, 0, 9189.0 / 3520.0, 3657.0 / 1760.0, 0}
+		assert.InDeltaSlice(t, expectedX, x, epsilon)
+		assert.Equal(t, []int{1, 3, 5, 7, 8, 11}, basis)
+	}
+
+	f := numbers.Rational{}
+	x, y, optimalValue, _, err := lp.RevisedSimplexOver(context.Background(), f, numbers.FromFloats(f, c), numbers.FromFloatMatrix(f, A), numbers.FromFloats(f, b), startbasis)
+	assert.NoError(t, err)
+	assertRatsEqual(t, rats("-59421/704"), []*big.Rat{optimalValue})
+	assertRatsEqual(t, rats("5677/880", "0", "0", "9189/3520", "3657/1760", "0"), x)
+	assertRatsEqual(t, rats("0", "2569/3520", "0", "47/880", "0", "39/1760", "0", "31803/880", "8973/704", "0", "0", "23863/1760"), y)
+}
+
+func TestRevisedSimplexAgreesWithSimplex(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	m, n := 60, 15
+
+	// random packing constraints A@x <= b with x >= 0, so x = 0 is a feasible start
+	A := make([][]float64, 0, m+n)
+	b := make([]float64, 0, m+n)
+	for range m {
+		row := make([]float64, n)
+		for j := range row {
+			row[j] = float64(random.Intn(20))
+		}
+		A = append(A, row)
+		b = append(b, float64(50+random.Intn(100)))
+	}
+	startbasis := make([]int, n)
+	for j := range n {
+		row := make([]float64, n)
+		row[j] = -1
+		A = append(A, row)
+		b = append(b, 0)
+		startbasis[j] = m + j
+	}
+	c := make([]float64, n)
+	for j := range c {
+		c[j] = float64(1 + random.Intn(10))
+	}
+
+	x, y, optimalValue, basis, err := lp.Simplex(c, A, b, startbasis)
+	assert.NoError(t, err)
+
+	revisedX, revisedY, revisedValue, revisedBasis, err := lp.RevisedSimplex(c, A, b, startbasis, lp.WithRefactorization(5))
+	assert.NoError(t, err)
+	assert.InDelta(t, optimalValue, revisedValue, 1e-6)
+	assert.InDeltaSlice(t, x, revisedX, 1e-6)
+	assert.InDeltaSlice(t, y, revisedY, 1e-6)
+	assert.Equal(t, basis, revisedBasis)
+}
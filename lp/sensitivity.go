@@ -0,0 +1,152 @@
+package lp
+
+import (
+	"errors"
+	"math"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrBasisNotOptimal = errors.New("the basis is not optimal")
+
+// A closed interval, where Lower may be -Inf and Upper +Inf.
+type Range struct {
+	Lower float64
+	Upper float64
+}
+
+func (r Range) Contains(v float64) bool {
+	return r.Lower <= v && v <= r.Upper
+}
+
+/*
+Post-optimal analysis of max c@x s.t. A@x <= b at an optimal basis, see Sensitivity.
+All slices of rows are indexed like the rows of A, all slices of variables like x.
+*/
+type SensitivityReport struct {
+	X         []float64
+	Objective float64
+	// the dual solution y: by how much the optimal value grows per unit that b_i grows
+	ShadowPrices []float64
+	// b_i - A_i@x, zero for the rows of the basis
+	Slacks []float64
+	/*
+		The reduced cost c_j - y@A_j of x_j, where y leaves out the non-negativity row -x_j <= 0, which
+		is the negated shadow price of that row: the rate at which the optimal value changes when x_j
+		is forced above zero, and by how much c_j has to grow before that pays off.
+		Zero if A has no such row or the row is not in the basis.
+	*/
+	ReducedCosts []float64
+	// the values of c_j for which the basis stays optimal, with all other coefficients unchanged
+	ObjectiveRanges []Range
+	/*
+		The values of b_i for which the basis stays optimal, with all other right hand sides unchanged.
+		Within that range the shadow price of row i stays valid and x moves linearly with b_i.
+	*/
+	RHSRanges []Range
+}
+
+/*
+Sensitivity analysis and ranging of max c@x s.t. A@x <= b at the optimal basis returned by Simplex.
+If the basis is not primal and dual feasible, ErrBasisNotOptimal is returned.
+
+With x = A_B^-1@b_B and y_B = A_B^-T@c, changing c_j by d changes y_B by d times the j-th row of A_B^-1,
+and changing b_i for a row i in the basis changes x by d times the column of A_B^-1 that belongs to i.
+The ranges are the values of d for which y_B >= 0 and A@x <= b still hold.
+*/
+func Sensitivity(c []float64, A [][]float64, b []float64, basis []int, opts ...Option) (report SensitivityReport, err error) {
+	if len(A) == 0 || len(A[0]) != len(c) || len(b) != len(A) || len(basis) != len(c) {
+		return report, ErrInvalidInput
+	}
+	o := collectOptions(opts)
+	tol := o.tolerance
+	m, n := len(A), len(c)
+
+	inverse, err := InvertOver(o.float64Field(), util.GetRows(A, basis))
+	if err != nil {
+		return report, err
+	}
+	x := util.MatMul(inverse, util.GetValues(b, basis))
+	y_B := util.MatMul(util.Transpose(inverse), c)
+
+	report.X = x
+	report.Objective = util.DotProduct(c, x)
+	report.ShadowPrices = make([]float64, m)
+	report.Slacks = make([]float64, m)
+	for k, i := range basis {
+		if tol.Sign(y_B[k]) < 0 {
+			return report, ErrBasisNotOptimal
+		}
+		report.ShadowPrices[i] = y_B[k]
+	}
+	for i, row := range A {
+		report.Slacks[i] = b[i] - util.DotProduct(row, x)
+		if tol.Sign(report.Slacks[i]) < 0 {
+			return report, ErrBasisNotOptimal
+		}
+	}
+
+	report.ReducedCosts = make([]float64, n)
+	for i, row := range A {
+		if j, ok := nonNegativityRow(row, b[i], tol); ok {
+			report.ReducedCosts[j] = row[j] * report.ShadowPrices[i]
+		}
+	}
+
+	// y_B + d*inverse[j] >= 0
+	report.ObjectiveRanges = make([]Range, n)
+	for j := range n {
+		lower, upper := stepRange(y_B, inverse[j], tol)
+		report.ObjectiveRanges[j] = Range{Lower: c[j] + lower, Upper: c[j] + upper}
+	}
+
+	report.RHSRanges = make([]Range, m)
+	for i := range m {
+		report.RHSRanges[i] = Range{Lower: b[i] - report.Slacks[i], Upper: math.Inf(1)}
+	}
+	for k, i := range basis {
+		// x moves along column k of the inverse, which changes the slack of row l by -d*A_l@column
+		column := util.GetColumn(inverse, k)
+		slopes := make([]float64, m)
+		for l, row := range A {
+			slopes[l] = -util.DotProduct(row, column)
+		}
+		// row i moves together with b_i and stays tight
+		slopes[i] = 0
+		lower, upper := stepRange(report.Slacks, slopes, tol)
+		report.RHSRanges[i] = Range{Lower: b[i] + lower, Upper: b[i] + upper}
+	}
+	return report, nil
+}
+
+// The interval of d for which v + d*slope >= 0, given v >= 0.
+func stepRange(v []float64, slope []float64, tol util.Tolerance) (lower float64, upper float64) {
+	lower, upper = math.Inf(-1), math.Inf(1)
+	for k := range v {
+		switch tol.Sign(slope[k]) {
+		case 1:
+			lower = max(lower, -max(v[k], 0)/slope[k])
+		case -1:
+			upper = min(upper, -max(v[k], 0)/slope[k])
+		}
+	}
+	return lower, upper
+}
+
+// Whether row@x <= rhs states x_j >= 0 for some j, scaled by a positive factor.
+func nonNegativityRow(row []float64, rhs float64, tol util.Tolerance) (j int, ok bool) {
+	if !tol.IsZero(rhs) {
+		return -1, false
+	}
+	j = -1
+	for k, v := range row {
+		if tol.IsZero(v) {
+			continue
+		}
+		if j != -1 || v > 0 {
+			return -1, false
+		}
+		j = k
+	}
+	return j, j != -1
+}
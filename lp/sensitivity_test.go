@@ -0,0 +1,65 @@
+package lp_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensitivity(t *testing.T) {
+	A := [][]float64{
+		{6, 4},
+		{1, 2},
+		{-1, 0},
+		{0, -1},
+	}
+	b := []float64{24, 6, 0, 0}
+	c := []float64{5, 4}
+
+	_, _, _, basis, err := lp.Simplex(c, A, b, []int{2, 3})
+	assert.NoError(t, err)
+	report, err := lp.Sensitivity(c, A, b, basis)
+	assert.NoError(t, err)
+
+	assert.InDeltaSlice(t, []float64{3, 1.5}, report.X, epsilon)
+	assert.InDelta(t, 21, report.Objective, epsilon)
+	assert.InDeltaSlice(t, []float64{0.75, 0.5, 0, 0}, report.ShadowPrices, epsilon)
+	assert.InDeltaSlice(t, []float64{0, 0, 3, 1.5}, report.Slacks, epsilon)
+	assert.InDeltaSlice(t, []float64{0, 0}, report.ReducedCosts, epsilon)
+
+	// the basis stays optimal while the slope c_1/c_2 stays between 1/2 and 6/4
+	assert.InDelta(t, 2, report.ObjectiveRanges[0].Lower, epsilon)
+	assert.InDelta(t, 6, report.ObjectiveRanges[0].Upper, epsilon)
+	assert.InDelta(t, 10.0/3, report.ObjectiveRanges[1].Lower, epsilon)
+	assert.InDelta(t, 10, report.ObjectiveRanges[1].Upper, epsilon)
+
+	assert.InDelta(t, 12, report.RHSRanges[0].Lower, epsilon)
+	assert.InDelta(t, 36, report.RHSRanges[0].Upper, epsilon)
+	assert.InDelta(t, 4, report.RHSRanges[1].Lower, epsilon)
+	assert.InDelta(t, 12, report.RHSRanges[1].Upper, epsilon)
+	assert.InDelta(t, -3, report.RHSRanges[2].Lower, epsilon)
+	assert.Equal(t, math.Inf(1), report.RHSRanges[2].Upper)
+
+	// within the range the shadow price predicts the optimal value
+	b[1] = 10
+	_, _, optimalValue, _, err := lp.Simplex(c, A, b, []int{2, 3})
+	assert.NoError(t, err)
+	assert.InDelta(t, 21+4*report.ShadowPrices[1], optimalValue, epsilon)
+	b[1] = 6
+
+	// with c = (1, 4) only x_2 is positive and x_1 would cost 1 per unit
+	c = []float64{1, 4}
+	_, _, _, basis, err = lp.Simplex(c, A, b, []int{2, 3})
+	assert.NoError(t, err)
+	report, err = lp.Sensitivity(c, A, b, basis)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 3}, report.X, epsilon)
+	assert.InDeltaSlice(t, []float64{-1, 0}, report.ReducedCosts, epsilon)
+	assert.True(t, report.ObjectiveRanges[0].Contains(2))
+	assert.False(t, report.ObjectiveRanges[0].Contains(2.5))
+
+	_, err = lp.Sensitivity(c, A, b, []int{2, 3})
+	assert.ErrorIs(t, err, lp.ErrBasisNotOptimal)
+}
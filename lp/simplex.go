@@ -1,129 +1,198 @@
 package lp
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"math"
-	"os"
 	"slices"
 
+	"github.com/JonasBernard/min-cost-max-flow/numbers"
 	"github.com/JonasBernard/min-cost-max-flow/util"
-	"github.com/jedib0t/go-pretty/v6/table"
 )
 
 var ErrUnbounded = errors.New("the linear program is unbounded")
+var ErrInfeasible = errors.New("the linear program is infeasible")
 var ErrMaxIterationsReached = errors.New("maximum number of iterations reached")
 
-const maxIterations = 1000
+const defaultMaxIterations = 1000
+
+/*
+Simplex method on a system in natural form max c@x s.t. A@x <= b using Bland's pivot rule,
+unless another one is chosen by WithPivotRule.
+If the program is unbounded, the error is an *UnboundedError[float64] that certifies it.
+*/
+func Simplex(c []float64, A [][]float64, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	return SimplexContext(context.Background(), c, A, b, startbasis, opts...)
+}
+
+/*
+Same as Simplex, but stops as soon as ctx is cancelled or its deadline passes.
+In that case the current vertex x (which is feasible, but not necessarily optimal), the current
+y values, the objective value at x and the current basis are returned together with an error
+that matches util.ErrInterrupted.
+*/
+func SimplexContext(ctx context.Context, c []float64, A [][]float64, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	x, y, optimalValue, endbasis, err = SimplexOver(ctx, collectOptions(opts).float64Field(), c, A, b, startbasis, opts...)
+	if errors.Is(err, ErrUnbounded) {
+		optimalValue = math.Inf(1)
+	}
+	return
+}
+
+/*
+Same as SimplexContext, but in the arithmetic of an arbitrary field.
+With numbers.Rational{} the optimal vertex x and the dual solution y are computed exactly.
+If the program is unbounded, the zero of the field is returned as optimal value.
+*/
+func SimplexOver[T any](ctx context.Context, f numbers.Field[T], c []T, A [][]T, b []T, startbasis []int, opts ...Option) (x []T, y []T, optimalValue T, endbasis []int, err error) {
+	o := collectOptions(opts)
 
-// Simplex method on a system in natural form max c@x s.t. A@x <= b using Bland's pivot rule
-func Simplex(c []float64, A [][]float64, b []float64, startbasis []int) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
 	basis := Basis{
 		Indices: make([]int, len(startbasis)),
-		YValues: make([]float64, len(A)),
 	}
 	copy(basis.Indices, startbasis)
+	yValues := filled(len(A), f.Zero())
 
 	A_B := util.GetRows(A, basis.Indices)
 	b_B := util.GetValues(b, basis.Indices)
-	x, error := SolveLinearSystem(A_B, b_B)
+	x, error := SolveLinearSystemOver(f, A_B, b_B)
 	if error != nil {
-		return nil, nil, 0, nil, error
+		return nil, nil, f.Zero(), nil, error
 	}
 
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-
-	t.AppendHeader(table.Row{"Iter", "Sol", "Basis", "Objective", "A_B", "A_B^T", "y_B", "Exit i", "A_B^-1", "w", "Aw", "jSelect", "Enter j", "gamma"})
+	rule := o.pivoting()
+	degenerate := 0
 
 	iter := 0
+	finish := func(status Status, objective T) {
+		o.observe(SolverFinished{Solver: "simplex", Iterations: iter, Status: status, Objective: f.Float(objective)})
+	}
+
 	for {
 		iter++
 
+		if err := util.Interrupted(ctx); err != nil {
+			finish(StatusInterrupted, dot(f, c, x))
+			return x, yValues, dot(f, c, x), basis.Indices, err
+		}
+		if o.exhausted(iter) {
+			finish(StatusMaxIterations, dot(f, c, x))
+			return x, yValues, dot(f, c, x), basis.Indices, ErrMaxIterationsReached
+		}
+
 		A_B = util.GetRows(A, basis.Indices)
 		A_BT := util.Transpose(A_B)
 
-		for i := range basis.YValues {
-			basis.YValues[i] = 0
+		for i := range yValues {
+			yValues[i] = f.Zero()
 		}
 
-		y_B, error := SolveLinearSystem(A_BT, c)
+		y_B, error := SolveLinearSystemOver(f, A_BT, c)
 		if error != nil {
-			return nil, nil, 0, basis.Indices, error
+			finish(StatusFailed, dot(f, c, x))
+			return nil, nil, f.Zero(), basis.Indices, error
+		}
+		for k, bk := range basis.Indices {
+			yValues[bk] = y_B[k]
+		}
+
+		event := SimplexIteration{Leaving: -1, Entering: -1}
+		if o.tracing() {
+			event.Iteration = iter
+			event.X = numbers.ToFloats(f, x)
+			event.Basis = slices.Clone(basis.Indices)
+			event.Objective = f.Float(dot(f, c, x))
+			event.BasisMatrix = numbers.ToFloatMatrix(f, A_B)
+			event.BasisY = numbers.ToFloats(f, y_B)
 		}
-		basis.injectY(y_B)
 
-		// This is Blands exiting rule because the first index is found.
-		i := util.Find(basis.YValues, func(v float64) bool { return v < 0.0 })
+		var candidates []PivotCandidate
+		for k, bk := range basis.Indices {
+			if f.Sign(y_B[k]) < 0 {
+				candidates = append(candidates, PivotCandidate{Row: bk, Price: -f.Float(y_B[k])})
+			}
+		}
 
-		if i == -1 {
-			t.AppendRow(table.Row{
-				iter, util.PrintVector(x),
-				fmt.Sprint(basis.Indices) + "\n" + fmt.Sprint(util.MapSlice(basis.Indices, func(t *int) int { return *t + 1 })),
-				util.DotProduct(c, x), util.PrintMatrix(A_B), util.PrintMatrix(A_BT), util.PrintVector(y_B), i,
-			})
-			t.AppendFooter(table.Row{"Optimal"})
-			t.Render()
-			return x, basis.YValues, util.DotProduct(c, x), basis.Indices, nil
+		if len(candidates) == 0 {
+			o.observe(event)
+			finish(StatusOptimal, dot(f, c, x))
+			return x, yValues, dot(f, c, x), basis.Indices, nil
 		}
 
-		inverted, error := Invert(A_B)
+		inverted, error := InvertOver(f, A_B)
 		if error != nil {
-			t.AppendFooter(table.Row{"Fatal", "error", "while", "matrix", "inversion"})
-			t.Render()
-			return nil, nil, 0, nil, error
+			o.observe(event)
+			finish(StatusFailed, dot(f, c, x))
+			return nil, nil, f.Zero(), nil, error
 		}
 
-		w := util.Neg(util.GetColumn(inverted, basis.ToBasisIndex(i)))
+		// the edge along which row B_k leaves the basis is the negated k-th column of A_B^-1
+		norm := func(k int) float64 {
+			column := util.GetColumn(inverted, basis.ToBasisIndex(candidates[k].Row))
+			return f.Float(dot(f, column, column))
+		}
+		i := selectPivot(rule, degenerate, candidates, norm)
+		event.Leaving = i
 
-		ratioTest := util.MatMul(A, w)
+		w := negVec(f, util.GetColumn(inverted, basis.ToBasisIndex(i)))
 
-		jSelect := util.FindAll(ratioTest, func(v float64) bool { return v > 0 })
+		ratioTest := matVec(f, A, w)
+
+		jSelect := util.FindAll(ratioTest, func(v T) bool { return f.Sign(v) > 0 })
+		if o.tracing() {
+			event.Inverse = numbers.ToFloatMatrix(f, inverted)
+			event.Direction = numbers.ToFloats(f, w)
+			event.Slopes = numbers.ToFloats(f, ratioTest)
+			event.Candidates = jSelect
+		}
 		if len(jSelect) == 0 {
-			t.AppendRow(table.Row{
-				iter, util.PrintVector(x),
-				fmt.Sprint(basis.Indices) + "\n" + fmt.Sprint(util.MapSlice(basis.Indices, func(t *int) int { return *t + 1 })),
-				util.DotProduct(c, x), util.PrintMatrix(A_B), util.PrintMatrix(A_BT), util.PrintVector(y_B), i, util.PrintMatrix(inverted), util.PrintVector(w), util.PrintVector(ratioTest), jSelect,
-			})
-			t.AppendFooter(table.Row{"Unbounded"})
-			t.Render()
-			return nil, nil, math.Inf(1), basis.Indices, ErrUnbounded
+			o.observe(event)
+			finish(StatusUnbounded, f.Zero())
+			// A@w <= 0 and c@w = -y_i > 0
+			return nil, nil, f.Zero(), basis.Indices, &UnboundedError[T]{X: x, Ray: w}
 		}
 
 		// Bland's entering rule
-		gamma := math.Inf(1)
+		var gamma T
 		j := -1
 		for _, jCandidate := range jSelect {
 			if util.Contains(basis.Indices, jCandidate) {
 				continue
 			}
-			ratio := (b[jCandidate] - util.DotProduct(util.GetRow(A, jCandidate), x)) / ratioTest[jCandidate]
+			ratio := f.Div(f.Sub(b[jCandidate], dot(f, util.GetRow(A, jCandidate), x)), ratioTest[jCandidate])
 
-			if ratio < gamma {
+			if j == -1 || f.Cmp(ratio, gamma) < 0 {
 				gamma = ratio
 				j = jCandidate
 			}
 		}
 
-		gamma_w := util.ScalarMult(gamma, w)
-
-		t.AppendRows([]table.Row{
-			{iter,
-				util.PrintVector(x),
-				fmt.Sprint(basis.Indices) + "\n" + fmt.Sprint(util.MapSlice(basis.Indices, func(t *int) int { return *t + 1 })),
-				util.DotProduct(c, x),
-				util.PrintMatrix(A_B),
-				util.PrintMatrix(A_BT),
-				util.PrintVector(y_B),
-				i,
-				util.PrintMatrix(inverted),
-				util.PrintVector(w),
-				util.PrintVector(ratioTest),
-				jSelect,
-				j,
-				gamma},
-		})
-		t.AppendSeparator()
+		gamma_w := scale(f, gamma, w)
+
+		event.Entering = j
+		if o.tracing() {
+			event.StepLength = f.Float(gamma)
+		}
+		o.observe(event)
+
+		if f.Sign(gamma) == 0 {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+
+		// the edges of the other rows of the basis change by a_j@(their edge) relative to a_j@w
+		ratios := func() map[int]float64 {
+			ratios := map[int]float64{j: 1 / f.Float(ratioTest[j])}
+			a_jInverted := matVec(f, util.Transpose(inverted), util.GetRow(A, j))
+			for k, bk := range basis.Indices {
+				if bk != i {
+					ratios[bk] = -f.Float(a_jInverted[k]) / f.Float(ratioTest[j])
+				}
+			}
+			return ratios
+		}
+		rule.Pivoted(i, ratios)
 
 		// Update basis
 		basis.Indices = util.RemoveValue(basis.Indices, i)
@@ -131,27 +200,70 @@ func Simplex(c []float64, A [][]float64, b []float64, startbasis []int) (x []flo
 
 		slices.Sort(basis.Indices)
 
-		x = util.VectorAdd(x, gamma_w)
+		x = addVec(f, x, gamma_w)
 	}
 }
 
-func Maximize(c []float64, A [][]float64, b []float64) (x []float64, optimalValue float64, err error) {
-	basis, feasible, err := PhaseOne(A, b, false)
+// Maximizes c@x s.t. A@x <= b, x >= 0, where a start basis is found by PhaseOne.
+func Maximize(c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, optimalValue float64, err error) {
+	return MaximizeContext(context.Background(), c, A, b, opts...)
+}
+
+/*
+Same as Maximize, but gives up once ctx is done. If that happens during the optimization
+phase, the best feasible vertex found so far is returned together with an error that matches
+util.ErrInterrupted. If it happens while still looking for a feasible start, x is nil.
+*/
+func MaximizeContext(ctx context.Context, c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, optimalValue float64, err error) {
+	o := collectOptions(opts)
+	if !o.presolve {
+		return MaximizeOver(ctx, o.float64Field(), c, A, b, opts...)
+	}
+	p, err := Presolve(c, A, b, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
+	x, _, optimalValue, err = p.SolveContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return x, optimalValue, nil
+}
+
+// Same as MaximizeContext, but in the arithmetic of an arbitrary field.
+func MaximizeOver[T any](ctx context.Context, f numbers.Field[T], c []T, A [][]T, b []T, opts ...Option) (x []T, optimalValue T, err error) {
+	basis, feasible, _, err := PhaseOneOver(ctx, f, A, b, false, opts...)
+	if err != nil {
+		return nil, f.Zero(), err
+	}
 	if !feasible {
-		return nil, 0, errors.New("the linear program is infeasible")
+		return nil, f.Zero(), ErrInfeasible
 	}
 
-	x, _, optimalValue, _, err = Simplex(c, A, b, basis)
+	// the basis of PhaseOne refers to the non-negativity constraints as well
+	n := len(c)
+	A_full := slices.Concat(A, negMatrix(f, identityMatrix(f, n)))
+	b_full := slices.Concat(b, filled(n, f.Zero()))
+
+	x, _, optimalValue, _, err = SimplexOver(ctx, f, c, A_full, b_full, basis, opts...)
+	if errors.Is(err, util.ErrInterrupted) {
+		return x, optimalValue, err
+	}
 	if err != nil {
-		return nil, 0, err
+		return nil, f.Zero(), err
 	}
 
 	return x, optimalValue, nil
 }
 
-func Minimize(c []float64, A [][]float64, b []float64) (x []float64, optimalValue float64, err error) {
-	return Maximize(util.Neg(c), A, b)
+func Minimize(c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, optimalValue float64, err error) {
+	return Maximize(util.Neg(c), A, b, opts...)
+}
+
+func MinimizeContext(ctx context.Context, c []float64, A [][]float64, b []float64, opts ...Option) (x []float64, optimalValue float64, err error) {
+	return MaximizeContext(ctx, util.Neg(c), A, b, opts...)
+}
+
+func MinimizeOver[T any](ctx context.Context, f numbers.Field[T], c []T, A [][]T, b []T, opts ...Option) (x []T, optimalValue T, err error) {
+	return MaximizeOver(ctx, f, negVec(f, c), A, b, opts...)
 }
@@ -1,10 +1,13 @@
 package lp_test
 
 import (
+	"bytes"
+	"context"
 	"math"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -45,7 +48,7 @@ func TestPhaseOne(t *testing.T) {
 	}
 	b := []float64{1, 3, -1}
 
-	basis, feasible, err := lp.PhaseOne(A, b, false)
+	basis, feasible, _, err := lp.PhaseOne(A, b, false)
 	assert.NoError(t, err)
 	assert.True(t, feasible, "Expected feasible solution")
 
@@ -53,7 +56,7 @@ func TestPhaseOne(t *testing.T) {
 	assert.Equal(t, expectedBasis, basis)
 }
 
-func NotTestMaximization(t *testing.T) {
+func TestMaximization(t *testing.T) {
 	A := [][]float64{
 		{0.5, -11.0 / 2.0, -5.0 / 2.0, 9},
 		{0.5, -3.0 / 2.0, -0.5, 1},
@@ -107,7 +110,7 @@ func TestSimplexExample91(t *testing.T) {
 	assert.Equal(t, expectedBasis, basis)
 }
 
-func TestSimplexExample91Dual(t *testing.T) { // broken I don't know
+func TestSimplexExample91Dual(t *testing.T) {
 	A := [][]float64{
 		{1, 0, 1},
 		{1, 1, 0},
@@ -118,7 +121,9 @@ func TestSimplexExample91Dual(t *testing.T) { // broken I don't know
 	}
 	b := []float64{8, 7, 12, 0, 0, 0}
 	c := []float64{3, 2, 2}
-	startbasis := []int{3, 4, 5}
+	// the dual simplex needs a dual feasible start, which x = 0 is not for c > 0, but the vertex (8, 2, 0)
+	// with y = (2, 1, 0) on its basis is, while it violates row 1
+	startbasis := []int{0, 2, 5}
 
 	x, _, optimalValue, basis, err := lp.DualSimplex(A, b, c, startbasis)
 	assert.NoError(t, err)
@@ -154,9 +159,9 @@ func TestSimplexExample92(t *testing.T) {
 	x, _, optimalValue, basis, err := lp.Simplex(c, A, b, startbasis)
 	assert.NoError(t, err)
 
-	assert.InDelta(t, -84.4048, optimalValue, epsilon)
+	assert.InDelta(t, -59421.0/704.0, optimalValue, epsilon) // -84.4048
 
-	expectedX := []float64{6.4511, 0, 0, 2.6105, 2.0778, 0}
+	expectedX := []float64{5677.0 / 880.0, 0, 0, 9189.0 / 3520.0, 3657.0 / 1760.0, 0} // 6.4511, 0, 0, 2.6105, 2.0778, 0
 	assert.InDeltaSlice(t, expectedX, x, epsilon)
 
 	expectedBasis := []int{1, 3, 5, 7, 8, 11}
@@ -261,3 +266,97 @@ func TestSimplexExample142(t *testing.T) {
 	expectedBasis := []int{2, 3, 6, 7}
 	assert.Equal(t, expectedBasis, basis)
 }
+
+func TestSimplexContextCancelled(t *testing.T) {
+	A := [][]float64{
+		{1, 0, 1},
+		{1, 1, 0},
+		{1, 2, 0},
+		{-1, 0, 0},
+		{0, -1, 0},
+		{0, 0, -1},
+	}
+	b := []float64{8, 7, 12, 0, 0, 0}
+	c := []float64{3, 2, 2}
+	startbasis := []int{3, 4, 5}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	x, _, optimalValue, basis, err := lp.SimplexContext(ctx, c, A, b, startbasis)
+	assert.ErrorIs(t, err, util.ErrInterrupted)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the start vertex is the incumbent
+	assert.InDeltaSlice(t, []float64{0, 0, 0}, x, epsilon)
+	assert.InDelta(t, 0, optimalValue, epsilon)
+	assert.Equal(t, startbasis, basis)
+}
+
+func TestSimplexMaxIterations(t *testing.T) {
+	A := [][]float64{
+		{1, 0, 1},
+		{1, 1, 0},
+		{1, 2, 0},
+		{-1, 0, 0},
+		{0, -1, 0},
+		{0, 0, -1},
+	}
+	b := []float64{8, 7, 12, 0, 0, 0}
+	c := []float64{3, 2, 2}
+
+	// one pivot leads to a better, but not yet optimal vertex
+	x, _, optimalValue, _, err := lp.Simplex(c, A, b, []int{3, 4, 5}, lp.WithMaxIterations(1))
+	assert.ErrorIs(t, err, lp.ErrMaxIterationsReached)
+	for i := range A {
+		assert.LessOrEqual(t, util.DotProduct(A[i], x), b[i]+epsilon)
+	}
+	assert.Greater(t, optimalValue, 0.0)
+	assert.Less(t, optimalValue, 28.0)
+
+	_, _, optimalValue, _, err = lp.Simplex(c, A, b, []int{3, 4, 5}, lp.WithMaxIterations(0))
+	assert.NoError(t, err)
+	assert.InDelta(t, 28, optimalValue, epsilon)
+
+	_, _, _, _, err = lp.DualSimplex(A, b, c, []int{0, 2, 5}, lp.WithMaxIterations(1))
+	assert.ErrorIs(t, err, lp.ErrMaxIterationsReached)
+}
+
+func TestSimplexObserver(t *testing.T) {
+	A := [][]float64{
+		{1, 0, 1},
+		{1, 1, 0},
+		{1, 2, 0},
+		{-1, 0, 0},
+		{0, -1, 0},
+		{0, 0, -1},
+	}
+	b := []float64{8, 7, 12, 0, 0, 0}
+	c := []float64{3, 2, 2}
+	startbasis := []int{3, 4, 5}
+
+	var iterations []lp.SimplexIteration
+	var finished []lp.SolverFinished
+	observer := trace.ObserverFunc(func(e trace.Event) {
+		switch e := e.(type) {
+		case lp.SimplexIteration:
+			iterations = append(iterations, e)
+		case lp.SolverFinished:
+			finished = append(finished, e)
+		}
+	})
+
+	var out bytes.Buffer
+	_, _, optimalValue, _, err := lp.Simplex(c, A, b, startbasis, lp.WithObserver(trace.Observers{observer, lp.NewTableObserver(&out)}))
+	assert.NoError(t, err)
+	assert.InDelta(t, 28, optimalValue, epsilon)
+
+	assert.NotEmpty(t, iterations)
+	assert.Equal(t, startbasis, iterations[0].Basis)
+	last := iterations[len(iterations)-1]
+	assert.Equal(t, -1, last.Leaving)
+	assert.InDelta(t, 28, last.Objective, epsilon)
+
+	assert.Equal(t, []lp.SolverFinished{{Solver: "simplex", Iterations: len(iterations), Status: lp.StatusOptimal, Objective: 28}}, finished)
+	assert.Contains(t, out.String(), "OPTIMAL")
+}
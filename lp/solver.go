@@ -0,0 +1,247 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+Solves a sequence of linear programs max c@x s.t. A@x <= b, x >= 0 that differ by added or removed
+rows and changed c or b. The basis of the last solve is kept and the next solve starts from it:
+
+  - Changing c keeps the basis primal feasible, so the primal simplex continues from it.
+  - Adding a row or changing b keeps it dual feasible, so the dual simplex continues from it.
+  - Removing a row of the basis moves along the edge that the remaining rows of the basis span
+    until another row becomes tight, which keeps the vertex primal feasible.
+
+Only when the basis is neither primal nor dual feasible after the changes, for example after
+changing c and b at once, PhaseOne is run again. Like PhaseOne, the basis refers to the rows of
+[A; -I], where row len(A)+j stands for x_j >= 0, so the indices shift when rows are added or removed.
+*/
+type Solver struct {
+	c     []float64
+	A     [][]float64
+	b     []float64
+	opts  []Option
+	basis Basis
+}
+
+// Creates a solver for max c@x s.t. A@x <= b, x >= 0. The options are used for every solve.
+func NewSolver(c []float64, A [][]float64, b []float64, opts ...Option) (*Solver, error) {
+	if len(A) == 0 || len(b) != len(A) {
+		return nil, ErrInvalidInput
+	}
+	rows := make([][]float64, len(A))
+	for i, row := range A {
+		if len(row) != len(c) {
+			return nil, ErrInvalidInput
+		}
+		rows[i] = slices.Clone(row)
+	}
+	return &Solver{
+		c:    slices.Clone(c),
+		A:    rows,
+		b:    slices.Clone(b),
+		opts: opts,
+	}, nil
+}
+
+// The basis of the last solve, or a zero Basis before the first one.
+func (s *Solver) Basis() Basis {
+	return Basis{Indices: slices.Clone(s.basis.Indices), YValues: slices.Clone(s.basis.YValues)}
+}
+
+// Adds the row a@x <= rhs and returns its index.
+func (s *Solver) AddRow(a []float64, rhs float64) (int, error) {
+	if len(a) != len(s.c) {
+		return -1, ErrInvalidInput
+	}
+	m := len(s.A)
+	s.A = append(s.A, slices.Clone(a))
+	s.b = append(s.b, rhs)
+	// the new row is inserted in front of -I
+	for k, i := range s.basis.Indices {
+		if i >= m {
+			s.basis.Indices[k]++
+		}
+	}
+	s.basis.YValues = nil
+	return m, nil
+}
+
+// Removes row i, so the rows behind it move up by one.
+func (s *Solver) RemoveRow(i int) error {
+	if i < 0 || i >= len(s.A) || len(s.A) == 1 {
+		return ErrInvalidInput
+	}
+	if slices.Contains(s.basis.Indices, i) {
+		s.replaceInBasis(i)
+	}
+	s.A = slices.Delete(s.A, i, i+1)
+	s.b = slices.Delete(s.b, i, i+1)
+	for k, l := range s.basis.Indices {
+		if l > i {
+			s.basis.Indices[k]--
+		}
+	}
+	s.basis.YValues = nil
+	return nil
+}
+
+/*
+Replaces row i of the basis by the row that becomes tight first when moving from the vertex of the
+basis along the edge of the other rows of the basis, in the direction the objective does not fall.
+If there is no such row, the basis is dropped and the next solve starts from scratch.
+*/
+func (s *Solver) replaceInBasis(i int) {
+	o := collectOptions(s.opts)
+	tol := o.tolerance
+	A, b := s.system()
+	inverse, err := InvertOver(o.float64Field(), util.GetRows(A, s.basis.Indices))
+	if err != nil {
+		s.basis = Basis{}
+		return
+	}
+	x := util.MatMul(inverse, util.GetValues(b, s.basis.Indices))
+	// column k of the inverse is orthogonal to all other rows of the basis
+	w := util.GetColumn(inverse, slices.Index(s.basis.Indices, i))
+	if tol.Sign(util.DotProduct(s.c, w)) < 0 {
+		w = util.Neg(w)
+	}
+
+	entering := -1
+	var step float64
+	for l, row := range A {
+		slope := util.DotProduct(row, w)
+		if l == i || slices.Contains(s.basis.Indices, l) || tol.Sign(slope) <= 0 {
+			continue
+		}
+		ratio := (b[l] - util.DotProduct(row, x)) / slope
+		if entering == -1 || tol.Less(ratio, step) {
+			entering, step = l, ratio
+		}
+	}
+	if entering == -1 {
+		s.basis = Basis{}
+		return
+	}
+	s.basis.Indices = util.RemoveValue(s.basis.Indices, i)
+	s.basis.Indices = append(s.basis.Indices, entering)
+	slices.Sort(s.basis.Indices)
+}
+
+func (s *Solver) SetObjective(c []float64) error {
+	if len(c) != len(s.c) {
+		return ErrInvalidInput
+	}
+	s.c = slices.Clone(c)
+	return nil
+}
+
+// Sets the right hand side of row i.
+func (s *Solver) SetRHS(i int, rhs float64) error {
+	if i < 0 || i >= len(s.b) {
+		return ErrInvalidInput
+	}
+	s.b[i] = rhs
+	return nil
+}
+
+func (s *Solver) Solve() (x []float64, optimalValue float64, err error) {
+	return s.SolveContext(context.Background())
+}
+
+/*
+Same as Solve, but gives up once ctx is done. The basis reached so far is kept, so the next solve
+continues from there. Like MaximizeContext, an interrupted primal simplex returns its feasible vertex.
+An interrupted dual simplex returns its basic solution, which is in general infeasible, and the
+objective value of its dual solution, an upper bound on the optimum, like DualSimplexContext.
+
+If the program is infeasible, the error is an *InfeasibleError[float64] that certifies it.
+*/
+func (s *Solver) SolveContext(ctx context.Context) (x []float64, optimalValue float64, err error) {
+	A, b := s.system()
+	primal, dual := s.warmStart(A, b)
+
+	switch {
+	case primal:
+		return s.primal(ctx, A, b)
+	case dual:
+		x, y, optimalValue, basis, err := DualSimplexContext(ctx, A, b, s.c, s.basis.Indices, s.opts...)
+		var unbounded *DualUnboundedError[float64]
+		if errors.As(err, &unbounded) {
+			// the basis stays dual feasible for the next solve, the ray of the dual combines the rows of A with those of -I
+			return nil, 0, &InfeasibleError[float64]{Farkas: unbounded.Ray[:len(s.A)]}
+		}
+		if basis != nil {
+			s.basis = Basis{Indices: basis, YValues: y}
+		}
+		if errors.Is(err, util.ErrInterrupted) {
+			return x, optimalValue, err
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return x, optimalValue, nil
+	}
+
+	basis, feasible, farkas, err := PhaseOneContext(ctx, s.A, s.b, false, s.opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !feasible {
+		s.basis = Basis{}
+		return nil, 0, &InfeasibleError[float64]{Farkas: farkas}
+	}
+	s.basis = Basis{Indices: basis}
+	return s.primal(ctx, A, b)
+}
+
+func (s *Solver) primal(ctx context.Context, A [][]float64, b []float64) (x []float64, optimalValue float64, err error) {
+	x, y, optimalValue, basis, err := SimplexContext(ctx, s.c, A, b, s.basis.Indices, s.opts...)
+	if basis != nil {
+		s.basis = Basis{Indices: basis, YValues: y}
+	}
+	if errors.Is(err, util.ErrInterrupted) {
+		return x, optimalValue, err
+	}
+	if err != nil {
+		return nil, optimalValue, err
+	}
+	return x, optimalValue, nil
+}
+
+// Whether the kept basis is primal and dual feasible for the current c, A and b.
+func (s *Solver) warmStart(A [][]float64, b []float64) (primal bool, dual bool) {
+	if len(s.basis.Indices) != len(s.c) {
+		return false, false
+	}
+	o := collectOptions(s.opts)
+	tol := o.tolerance
+	inverse, err := InvertOver(o.float64Field(), util.GetRows(A, s.basis.Indices))
+	if err != nil {
+		return false, false
+	}
+
+	x := util.MatMul(inverse, util.GetValues(b, s.basis.Indices))
+	primal = true
+	for i, row := range A {
+		if tol.Greater(util.DotProduct(row, x), b[i]) {
+			primal = false
+			break
+		}
+	}
+
+	y_B := util.MatMul(util.Transpose(inverse), s.c)
+	dual = !slices.ContainsFunc(y_B, func(v float64) bool { return tol.Sign(v) < 0 })
+	return primal, dual
+}
+
+// The system [A; -I]@x <= [b; 0] the basis refers to.
+func (s *Solver) system() (A [][]float64, b []float64) {
+	n := len(s.c)
+	return slices.Concat(s.A, util.NegMatrix(util.IdentityMatrix(n))), slices.Concat(s.b, make([]float64, n))
+}
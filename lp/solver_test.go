@@ -0,0 +1,157 @@
+package lp_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records which solvers ran.
+type solverRecorder struct {
+	solvers []string
+}
+
+func (r *solverRecorder) Observe(e trace.Event) {
+	if finished, ok := e.(lp.SolverFinished); ok {
+		r.solvers = append(r.solvers, finished.Solver)
+	}
+}
+
+func TestSolverWarmStart(t *testing.T) {
+	recorder := &solverRecorder{}
+	s, err := lp.NewSolver([]float64{5, 4}, [][]float64{{6, 4}, {1, 2}}, []float64{24, 6}, lp.WithObserver(recorder))
+	assert.NoError(t, err)
+
+	x, optimalValue, err := s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{3, 1.5}, x, epsilon)
+	assert.InDelta(t, 21, optimalValue, epsilon)
+	assert.Equal(t, []int{0, 1}, s.Basis().Indices)
+	// PhaseOne and the simplex
+	assert.Equal(t, []string{"simplex", "simplex"}, recorder.solvers)
+
+	// x_2 <= 1 cuts the vertex off, the dual simplex continues
+	recorder.solvers = nil
+	row, err := s.AddRow([]float64{0, 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, row)
+	x, optimalValue, err = s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{10.0 / 3, 1}, x, epsilon)
+	assert.InDelta(t, 62.0/3, optimalValue, epsilon)
+	assert.Equal(t, []string{"dual simplex"}, recorder.solvers)
+
+	// a changed b keeps the basis dual feasible as well
+	recorder.solvers = nil
+	assert.NoError(t, s.SetRHS(0, 30))
+	x, optimalValue, err = s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{4.5, 0.75}, x, epsilon)
+	assert.InDelta(t, 25.5, optimalValue, epsilon)
+	assert.Equal(t, []string{"dual simplex"}, recorder.solvers)
+
+	// a changed c keeps it primal feasible
+	recorder.solvers = nil
+	assert.NoError(t, s.SetObjective([]float64{1, 4}))
+	x, optimalValue, err = s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{4, 1}, x, epsilon)
+	assert.InDelta(t, 8, optimalValue, epsilon)
+	assert.Equal(t, []int{1, 2}, s.Basis().Indices)
+	assert.Equal(t, []string{"simplex"}, recorder.solvers)
+
+	// removing x_2 <= 1 from the basis moves along the other tight row x_1 + 2x_2 <= 6
+	recorder.solvers = nil
+	assert.NoError(t, s.RemoveRow(2))
+	x, optimalValue, err = s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0, 3}, x, epsilon)
+	assert.InDelta(t, 12, optimalValue, epsilon)
+	assert.Equal(t, []string{"simplex"}, recorder.solvers)
+
+	// infeasible, and feasible again once the contradicting row is gone
+	row, err = s.AddRow([]float64{-1, -1}, -7)
+	assert.NoError(t, err)
+	_, _, err = s.Solve()
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+	var infeasible *lp.InfeasibleError[float64]
+	if assert.ErrorAs(t, err, &infeasible) {
+		A, b := [][]float64{{6, 4}, {1, 2}, {-1, -1}}, []float64{30, 6, -7}
+		assertFarkas(t, A, b, infeasible.Farkas)
+	}
+	assert.NoError(t, s.RemoveRow(row))
+	_, optimalValue, err = s.Solve()
+	assert.NoError(t, err)
+	assert.InDelta(t, 12, optimalValue, epsilon)
+
+	assert.ErrorIs(t, s.RemoveRow(5), lp.ErrInvalidInput)
+	_, err = s.AddRow([]float64{1}, 1)
+	assert.ErrorIs(t, err, lp.ErrInvalidInput)
+}
+
+func TestSolverInterrupted(t *testing.T) {
+	s, err := lp.NewSolver([]float64{5, 4}, [][]float64{{6, 4}, {1, 2}}, []float64{24, 6})
+	assert.NoError(t, err)
+	_, _, err = s.Solve()
+	assert.NoError(t, err)
+
+	// the dual simplex is interrupted before its first pivot
+	_, err = s.AddRow([]float64{0, 1}, 1)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	x, bound, err := s.SolveContext(ctx)
+	assert.ErrorIs(t, err, util.ErrInterrupted)
+	assert.InDeltaSlice(t, []float64{3, 1.5}, x, epsilon)
+	assert.InDelta(t, 21, bound, epsilon)
+
+	x, optimalValue, err := s.Solve()
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{10.0 / 3, 1}, x, epsilon)
+	assert.InDelta(t, 62.0/3, optimalValue, epsilon)
+}
+
+func TestSolverMatchesMaximize(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+	n := 4
+	c := []float64{3, 2, 4, 1}
+	A := [][]float64{{1, 1, 1, 1}}
+	b := []float64{20}
+	s, err := lp.NewSolver(c, A, b)
+	assert.NoError(t, err)
+
+	for step := range 30 {
+		switch {
+		case step%5 == 4 && len(A) > 1:
+			i := 1 + random.Intn(len(A)-1)
+			A = append(A[:i:i], A[i+1:]...)
+			b = append(b[:i:i], b[i+1:]...)
+			assert.NoError(t, s.RemoveRow(i))
+		case step%3 == 2:
+			i := random.Intn(len(b))
+			b[i] = float64(5 + random.Intn(20))
+			assert.NoError(t, s.SetRHS(i, b[i]))
+		default:
+			row := make([]float64, n)
+			for j := range row {
+				row[j] = float64(random.Intn(7) - 1)
+			}
+			A = append(A, row)
+			b = append(b, float64(5+random.Intn(20)))
+			_, err := s.AddRow(row, b[len(b)-1])
+			assert.NoError(t, err)
+		}
+
+		_, expected, expectedErr := lp.Maximize(c, A, b)
+		_, optimalValue, err := s.Solve()
+		assert.Equal(t, expectedErr, err)
+		if expectedErr == nil {
+			assert.InDelta(t, expected, optimalValue, 1e-6)
+		}
+	}
+}
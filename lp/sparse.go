@@ -0,0 +1,202 @@
+package lp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+type sparseRows struct {
+	A *util.CSR
+}
+
+func (s sparseRows) rows() int                         { return s.A.Rows }
+func (s sparseRows) row(i int) []float64               { return s.A.DenseRow(i) }
+func (s sparseRows) rowDot(i int, v []float64) float64 { return s.A.RowDot(i, v) }
+func (s sparseRows) mulVec(v []float64) []float64      { return s.A.MulVec(v) }
+
+/*
+Same as Simplex, but A is given as sparse matrix. The pivots are carried out by the revised simplex
+(see RevisedSimplex), so A is never stored densely, only the n x n basis matrix is.
+*/
+func SimplexSparse(c []float64, A *util.CSR, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	return SimplexSparseContext(context.Background(), c, A, b, startbasis, opts...)
+}
+
+// Same as SimplexSparse, but stops as soon as ctx is done, see SimplexContext.
+func SimplexSparseContext(ctx context.Context, c []float64, A *util.CSR, b []float64, startbasis []int, opts ...Option) (x []float64, y []float64, optimalValue float64, endbasis []int, err error) {
+	if A.Cols != len(c) || A.Rows != len(b) {
+		return nil, nil, 0, nil, ErrInvalidInput
+	}
+	x, y, optimalValue, endbasis, err = revisedSimplex(ctx, collectOptions(opts).float64Field(), c, sparseRows{A}, b, startbasis, opts...)
+	if errors.Is(err, ErrUnbounded) {
+		optimalValue = math.Inf(1)
+	}
+	return
+}
+
+/*
+Same as PhaseOne on a sparse matrix A, using the primal simplex on a sparse auxiliary problem.
+The Farkas certificate of an infeasible system is returned densely.
+*/
+func PhaseOneSparse(A *util.CSR, b []float64, opts ...Option) (basis []int, feasible bool, farkas []float64, err error) {
+	return PhaseOneSparseContext(context.Background(), A, b, opts...)
+}
+
+// Same as PhaseOneSparse, but gives up once ctx is done, see PhaseOneContext.
+func PhaseOneSparseContext(ctx context.Context, A *util.CSR, b []float64, opts ...Option) (basis []int, feasible bool, farkas []float64, err error) {
+	f := collectOptions(opts).float64Field()
+	pos_rows := util.FindAll(b, func(row float64) bool { return f.Sign(row) >= 0 })
+	neg_rows := util.FindAll(b, func(row float64) bool { return f.Sign(row) < 0 })
+
+	m := A.Rows
+	n := A.Cols
+	m_plus := len(pos_rows)
+	m_minus := len(neg_rows)
+
+	// the same auxiliary system as in PhaseOneOver, assembled entry by entry
+	var rows, cols []int
+	var values []float64
+	add := func(i int, j int, v float64) {
+		rows = append(rows, i)
+		cols = append(cols, j)
+		values = append(values, v)
+	}
+	c := make([]float64, n+m_minus)
+	for k, i := range pos_rows {
+		columns, vals := A.Row(i)
+		for l, j := range columns {
+			add(k, j, vals[l])
+		}
+	}
+	for k, i := range neg_rows {
+		columns, vals := A.Row(i)
+		for l, j := range columns {
+			add(m_plus+k, j, -vals[l])
+			c[j] -= vals[l]
+		}
+		add(m_plus+k, n+k, -1)
+		add(m+n+k, n+k, -1)
+		c[n+k] = -1
+	}
+	for j := range n {
+		add(m+j, j, -1)
+	}
+	D := util.NewCSRFromTriplets(m+n+m_minus, n+m_minus, rows, cols, values)
+	d := slices.Concat(util.GetValues(b, pos_rows), util.Neg(util.GetValues(b, neg_rows)), make([]float64, n+m_minus))
+
+	startbasis := make([]int, n+m_minus)
+	for i := range startbasis {
+		startbasis[i] = m + i
+	}
+
+	_, y, optimalValue, resultbasis, err := SimplexSparseContext(ctx, c, D, d, startbasis, opts...)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	if f.Cmp(-optimalValue, sum(f, util.GetValues(b, neg_rows))) > 0 {
+		// see PhaseOneOver
+		farkas = make([]float64, m)
+		for k, i := range pos_rows {
+			farkas[i] = y[k]
+		}
+		for k, i := range neg_rows {
+			farkas[i] = y[m+n+k]
+		}
+		return nil, false, farkas, nil
+	}
+
+	return phaseOneBasis(resultbasis, m, n, pos_rows, neg_rows), true, nil, nil
+}
+
+/*
+Same as GaussElimination on a sparse matrix. Rows are only touched where they have a non-zero
+entry in the pivot column, so the elimination keeps the sparsity as far as the fill-in allows.
+*/
+func GaussEliminationSparse(A *util.CSR, b []float64, opts ...Option) (RU *util.CSR, bU []float64, err error) {
+	N := A.Rows
+	if N == 0 || A.Cols != N || len(b) != N {
+		return nil, nil, ErrInvalidInput
+	}
+	tol := collectOptions(opts).tolerance
+
+	RUrows := make([]map[int]float64, N)
+	for i := range N {
+		columns, values := A.Row(i)
+		RUrows[i] = make(map[int]float64, len(columns))
+		for k, j := range columns {
+			RUrows[i][j] = values[k]
+		}
+	}
+	bU = slices.Clone(b)
+
+	for col := 0; col < N; col++ {
+		i_max := col
+		for row := col + 1; row < N; row++ {
+			if tol.Greater(math.Abs(RUrows[row][col]), math.Abs(RUrows[i_max][col])) {
+				i_max = row
+			}
+		}
+		if tol.Sign(RUrows[i_max][col]) == 0 {
+			return nil, nil, ErrSingularMatrix
+		}
+		RUrows[col], RUrows[i_max] = RUrows[i_max], RUrows[col]
+		bU[col], bU[i_max] = bU[i_max], bU[col]
+
+		pivot := RUrows[col]
+		for row := col + 1; row < N; row++ {
+			v, ok := RUrows[row][col]
+			if !ok {
+				continue
+			}
+			ratio := v / pivot[col]
+			for j, p := range pivot {
+				if j > col {
+					RUrows[row][j] -= ratio * p
+				}
+			}
+			bU[row] -= ratio * bU[col]
+			delete(RUrows[row], col)
+		}
+	}
+
+	var rows, cols []int
+	var values []float64
+	for i, row := range RUrows {
+		for j, v := range row {
+			rows = append(rows, i)
+			cols = append(cols, j)
+			values = append(values, v)
+		}
+	}
+	return util.NewCSRFromTriplets(N, N, rows, cols, values), bU, nil
+}
+
+func SolveLinearSystemSparse(A *util.CSR, b []float64, opts ...Option) (x []float64, err error) {
+	RU, b, err := GaussEliminationSparse(A, b, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	N := RU.Rows
+	x = make([]float64, N)
+
+	for i := N - 1; i >= 0; i-- {
+		x[i] = b[i]
+		diagonal := 0.0
+		columns, values := RU.Row(i)
+		for k, j := range columns {
+			if j == i {
+				diagonal = values[k]
+			} else {
+				x[i] -= values[k] * x[j]
+			}
+		}
+		x[i] /= diagonal
+	}
+	return x, nil
+}
@@ -0,0 +1,122 @@
+package lp_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolveLinearSystemSparse(t *testing.T) {
+	A := [][]float64{
+		{0, 1, 0, 2},
+		{3, 0, 0, 0},
+		{0, 0, 4, 1},
+		{1, 0, 0, 5},
+	}
+	b := []float64{1, 2, 3, 4}
+
+	x, err := lp.SolveLinearSystemSparse(util.NewCSR(A, 4), b)
+	assert.NoError(t, err)
+	expected, _ := lp.SolveLinearSystem(A, b)
+	assert.InDeltaSlice(t, expected, x, epsilon)
+
+	_, err = lp.SolveLinearSystemSparse(util.NewCSR([][]float64{{1, 2}, {2, 4}}, 2), []float64{1, 2})
+	assert.ErrorIs(t, err, lp.ErrSingularMatrix)
+}
+
+func TestSimplexSparseExample92(t *testing.T) {
+	A := util.NewCSR([][]float64{
+		{110, 205, 160, 160, 420, 260},
+		{4, 32, 13, 8, 4, 14},
+		{2, 12, 54, 284, 22, 80},
+		{-110, -205, -160, -160, -420, -260},
+		{-4, -32, -13, -8, -4, -14},
+		{-2, -12, -54, -284, -22, -80},
+		{-1, 0, 0, 0, 0, 0},
+		{0, -1, 0, 0, 0, 0},
+		{0, 0, -1, 0, 0, 0},
+		{0, 0, 0, -1, 0, 0},
+		{0, 0, 0, 0, -1, 0},
+		{0, 0, 0, 0, 0, -1},
+	}, 6)
+	b := []float64{2000, 55, 800, -2000, -55, -800, 0, 0, 0, 0, 0, 0}
+	c := []float64{-3, -24, -13, -9, -20, -19}
+
+	x, _, optimalValue, basis, err := lp.SimplexSparse(c, A, b, []int{0, 1, 2, 6, 7, 11})
+	assert.NoError(t, err)
+	assert.InDelta(t, -59421.0/704.0, optimalValue, epsilon)
+	assert.InDeltaSlice(t, []float64{5677.0 / 880.0, 0, 0, 9189.0 / 3520.0, 3657.0 / 1760.0, 0}, x, epsilon)
+	assert.Equal(t, []int{1, 3, 5, 7, 8, 11}, basis)
+}
+
+func TestPhaseOneSparse(t *testing.T) {
+	A := [][]float64{
+		{-1, -1},
+		{3, -1},
+		{-3, 1},
+		{4, 2},
+	}
+	b := []float64{-1, 20, -20, 100}
+
+	basis, feasible, _, err := lp.PhaseOneSparse(util.NewCSR(A, 2), b)
+	assert.NoError(t, err)
+	assert.True(t, feasible)
+
+	denseBasis, _, _, _ := lp.PhaseOne(A, b, false)
+	assert.Equal(t, denseBasis, basis)
+
+	// the basis refers to [A; -I] in the original row order
+	_, _, optimalValue, _, err := lp.Simplex([]float64{2, 1}, append(A, []float64{-1, 0}, []float64{0, -1}), append(b, 0, 0), basis)
+	assert.NoError(t, err)
+	assert.InDelta(t, 50, optimalValue, epsilon)
+
+	_, feasible, farkas, err := lp.PhaseOneSparse(util.NewCSR([][]float64{{1, 1}}, 2), []float64{-1})
+	assert.NoError(t, err)
+	assert.False(t, feasible)
+	assert.Len(t, farkas, 1)
+	assert.Greater(t, farkas[0], 0.0)
+}
+
+func TestSimplexSparseAgreesWithSimplex(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	m, n := 200, 20
+
+	var rows, cols []int
+	var values []float64
+	b := make([]float64, 0, m+n)
+	for i := range m {
+		// about 10% non-zeros per row
+		for j := range n {
+			if random.Intn(10) == 0 {
+				rows = append(rows, i)
+				cols = append(cols, j)
+				values = append(values, float64(1+random.Intn(9)))
+			}
+		}
+		b = append(b, float64(10+random.Intn(40)))
+	}
+	startbasis := make([]int, n)
+	for j := range n {
+		rows = append(rows, m+j)
+		cols = append(cols, j)
+		values = append(values, -1)
+		b = append(b, 0)
+		startbasis[j] = m + j
+	}
+	A := util.NewCSRFromTriplets(m+n, n, rows, cols, values)
+	c := make([]float64, n)
+	for j := range c {
+		c[j] = float64(1 + random.Intn(5))
+	}
+
+	x, _, optimalValue, _, err := lp.Simplex(c, A.Dense(), b, startbasis)
+	assert.NoError(t, err)
+
+	sparseX, _, sparseValue, _, err := lp.SimplexSparse(c, A, b, startbasis)
+	assert.NoError(t, err)
+	assert.InDelta(t, optimalValue, sparseValue, 1e-6)
+	assert.InDeltaSlice(t, x, sparseX, 1e-6)
+}
@@ -0,0 +1,289 @@
+package lp
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type Status string
+
+const (
+	StatusOptimal       Status = "optimal"
+	StatusUnbounded     Status = "unbounded"
+	StatusInfeasible    Status = "infeasible"
+	StatusInterrupted   Status = "interrupted"
+	StatusMaxIterations Status = "maximum number of iterations reached"
+	StatusFailed        Status = "failed"
+)
+
+/*
+Reported by Simplex once per iteration. All indices are global row indices of A.
+Leaving is the row that leaves the basis and Entering the row that enters it.
+Both are -1 if the iteration ended the algorithm (then the fields from Inverse onwards are
+unset as far as they were not computed).
+RevisedSimplex reports the same events, but never forms BasisMatrix and Inverse explicitly.
+*/
+type SimplexIteration struct {
+	Iteration   int
+	X           []float64
+	Basis       []int
+	Objective   float64
+	BasisMatrix [][]float64
+	BasisY      []float64
+	Leaving     int
+	Inverse     [][]float64
+	Direction   []float64
+	Slopes      []float64
+	Candidates  []int
+	Entering    int
+	StepLength  float64
+}
+
+func (e SimplexIteration) Kind() string {
+	return "simplex iteration"
+}
+
+func (e SimplexIteration) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.Any("basis", e.Basis),
+		slog.Float64("objective", e.Objective),
+		slog.Int("leaving", e.Leaving),
+		slog.Int("entering", e.Entering),
+		slog.Float64("step", e.StepLength),
+	}
+}
+
+/*
+Reported by DualSimplex once per iteration. All indices are global row indices of A.
+Entering is the violated row that enters the basis and Leaving the row that leaves it.
+Both are -1 if the iteration ended the algorithm.
+*/
+type DualSimplexIteration struct {
+	Iteration      int
+	Basis          []int
+	NonBasis       []int
+	Y              []float64
+	BasisMatrix    [][]float64
+	X              []float64
+	NonBasisMatrix [][]float64
+	Slacks         []float64
+	Entering       int
+	Direction      []float64
+	Leaving        int
+	StepLength     float64
+	Objective      float64
+}
+
+func (e DualSimplexIteration) Kind() string {
+	return "dual simplex iteration"
+}
+
+func (e DualSimplexIteration) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.Any("basis", e.Basis),
+		slog.Float64("objective", e.Objective),
+		slog.Int("leaving", e.Leaving),
+		slog.Int("entering", e.Entering),
+		slog.Float64("step", e.StepLength),
+	}
+}
+
+/*
+Reported by BranchAndBound for every node it solves. Relaxation is the optimal value of the node's
+linear relaxation, Incumbent the value of the best integral solution after processing the node.
+Outcome is one of "infeasible", "pruned", "integral" and "branched", where Variable is the
+variable branched on (-1 otherwise).
+*/
+type BranchAndBoundNode struct {
+	Node       int
+	Depth      int
+	Relaxation float64
+	Incumbent  float64
+	Outcome    string
+	Variable   int
+	Cuts       int
+}
+
+func (e BranchAndBoundNode) Kind() string {
+	return "branch and bound node"
+}
+
+func (e BranchAndBoundNode) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("node", e.Node),
+		slog.Int("depth", e.Depth),
+		slog.Float64("relaxation", e.Relaxation),
+		slog.Float64("incumbent", e.Incumbent),
+		slog.String("outcome", e.Outcome),
+		slog.Int("variable", e.Variable),
+		slog.Int("cuts", e.Cuts),
+	}
+}
+
+/*
+Reported by InteriorPoint once per iteration. Objective is c@x at the current, not necessarily
+feasible iterate, Mu the average complementarity x_j*w_j and Sigma the centering parameter of the
+step. The residuals are the norms of the violations of the primal and dual equations.
+*/
+type InteriorPointIteration struct {
+	Iteration      int
+	Objective      float64
+	PrimalResidual float64
+	DualResidual   float64
+	Mu             float64
+	Sigma          float64
+	StepPrimal     float64
+	StepDual       float64
+}
+
+func (e InteriorPointIteration) Kind() string {
+	return "interior point iteration"
+}
+
+func (e InteriorPointIteration) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.Float64("objective", e.Objective),
+		slog.Float64("primal residual", e.PrimalResidual),
+		slog.Float64("dual residual", e.DualResidual),
+		slog.Float64("mu", e.Mu),
+		slog.Float64("sigma", e.Sigma),
+		slog.Float64("primal step", e.StepPrimal),
+		slog.Float64("dual step", e.StepDual),
+	}
+}
+
+/*
+Reported by Ellipsoid once per iteration. Center is the center of the current ellipsoid and Cut the
+inequality the oracle returned for it (nil if the center is in the set). Depth is the depth alpha of
+the cut and Volume the logarithm of the volume of the ellipsoid relative to the unit ball.
+*/
+type EllipsoidIteration struct {
+	Iteration int
+	Center    []float64
+	Cut       []float64
+	Depth     float64
+	Volume    float64
+}
+
+func (e EllipsoidIteration) Kind() string {
+	return "ellipsoid iteration"
+}
+
+func (e EllipsoidIteration) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.Any("center", e.Center),
+		slog.Float64("depth", e.Depth),
+		slog.Float64("volume", e.Volume),
+	}
+}
+
+// Reported once by every solver run when it returns.
+type SolverFinished struct {
+	Solver     string
+	Iterations int
+	Status     Status
+	Objective  float64
+}
+
+func (e SolverFinished) Kind() string {
+	return "solver finished"
+}
+
+func (e SolverFinished) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String("solver", e.Solver),
+		slog.Int("iterations", e.Iterations),
+		slog.String("status", string(e.Status)),
+		slog.Float64("objective", e.Objective),
+	}
+}
+
+/*
+Renders the iterations of every solver run as one table, like the solvers of this package
+used to do on stdout. The table of a run is written once the run finishes.
+*/
+type TableObserver struct {
+	out   io.Writer
+	table table.Writer
+}
+
+func NewTableObserver(out io.Writer) *TableObserver {
+	return &TableObserver{out: out}
+}
+
+func (o *TableObserver) start(header table.Row) {
+	if o.table != nil {
+		return
+	}
+	o.table = table.NewWriter()
+	o.table.SetOutputMirror(o.out)
+	o.table.AppendHeader(header)
+}
+
+func (o *TableObserver) Observe(e trace.Event) {
+	switch e := e.(type) {
+	case SimplexIteration:
+		o.start(table.Row{"Iter", "Sol", "Basis", "Objective", "A_B", "A_B^T", "y_B", "Exit i", "A_B^-1", "w", "Aw", "jSelect", "Enter j", "gamma"})
+		row := table.Row{
+			e.Iteration,
+			util.PrintVector(e.X),
+			fmt.Sprint(e.Basis) + "\n" + fmt.Sprint(util.MapSlice(e.Basis, func(t *int) int { return *t + 1 })),
+			e.Objective,
+			util.PrintMatrix(e.BasisMatrix),
+			util.PrintMatrix(util.Transpose(e.BasisMatrix)),
+			util.PrintVector(e.BasisY),
+			e.Leaving,
+		}
+		if e.Direction != nil {
+			row = append(row, util.PrintMatrix(e.Inverse), util.PrintVector(e.Direction), util.PrintVector(e.Slopes), e.Candidates)
+		}
+		if e.Entering != -1 {
+			row = append(row, e.Entering, e.StepLength)
+		}
+		o.table.AppendRow(row)
+		o.table.AppendSeparator()
+	case DualSimplexIteration:
+		o.start(table.Row{"Iter", "Basis", "Nonbasis", "y", "A_B", "x", "A_N", "z_N", "j", "w_B", "i", "gamma", "Dual objective"})
+		row := table.Row{
+			e.Iteration,
+			fmt.Sprint(e.Basis) + "\n" + fmt.Sprint(util.MapSlice(e.Basis, func(t *int) int { return *t + 1 })),
+			e.NonBasis,
+			util.PrintVector(e.Y),
+			util.PrintMatrix(e.BasisMatrix),
+			e.X,
+			util.PrintMatrix(e.NonBasisMatrix),
+			util.PrintVector(e.Slacks),
+		}
+		if e.Entering == -1 {
+			row = append(row, "", "", "", "")
+		} else {
+			row = append(row, e.Entering, util.PrintVector(e.Direction), e.Leaving, e.StepLength)
+		}
+		row = append(row, e.Objective)
+		o.table.AppendRow(row)
+		o.table.AppendSeparator()
+	case BranchAndBoundNode:
+		o.start(table.Row{"Node", "Depth", "Relaxation", "Incumbent", "Outcome", "Variable", "Cuts"})
+		o.table.AppendRow(table.Row{e.Node, e.Depth, e.Relaxation, e.Incumbent, e.Outcome, e.Variable, e.Cuts})
+	case InteriorPointIteration:
+		o.start(table.Row{"Iter", "Objective", "Primal residual", "Dual residual", "mu", "sigma", "Primal step", "Dual step"})
+		o.table.AppendRow(table.Row{e.Iteration, e.Objective, e.PrimalResidual, e.DualResidual, e.Mu, e.Sigma, e.StepPrimal, e.StepDual})
+	case EllipsoidIteration:
+		o.start(table.Row{"Iter", "Center", "Cut", "alpha", "ln vol"})
+		o.table.AppendRow(table.Row{e.Iteration, util.PrintVector(e.Center), util.PrintVector(e.Cut), e.Depth, e.Volume})
+	case SolverFinished:
+		o.start(table.Row{"Iter"})
+		o.table.AppendFooter(table.Row{e.Solver, e.Status})
+		o.table.Render()
+		o.table = nil
+	}
+}
@@ -32,7 +32,7 @@ type MatchingEdge[L graph.Node, R graph.Node] struct {
 	Right R
 }
 
-func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode L, rightNode R) (connect bool, weights float64), capacities func(rightNode R) (capacitiy float64)) (net network.WeigthedNetwork[MatchNode[L, R]], source graph.Vertex[MatchNode[L, R]], sink graph.Vertex[MatchNode[L, R]]) {
+func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode L, rightNode R) (connect bool, weights float64), capacities func(rightNode R) (capacitiy float64)) (net network.WeigthedNetwork[MatchNode[L, R], float64], source graph.Vertex[MatchNode[L, R]], sink graph.Vertex[MatchNode[L, R]]) {
 	leftNodes := util.MapSlice(m.Lefts, func(l *L) graph.Vertex[MatchNode[L, R]] {
 		return graph.V(&MatchNode[L, R]{
 			Name:      (*l).String(),
@@ -50,7 +50,7 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 	})
 
 	// the capacity cannot be known beforehand because it depends on what the choices are
-	allEdges := make([]*graph.WeightedDirectedEdge[MatchNode[L, R]], 0, 4*len(leftNodes)+len(rightNodes))
+	allEdges := make([]*graph.WeightedDirectedEdge[MatchNode[L, R], float64], 0, 4*len(leftNodes)+len(rightNodes))
 
 	source = graph.V(&MatchNode[L, R]{
 		Name: "S", IsSource: true,
@@ -61,7 +61,7 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 	})
 
 	for _, leftNode := range leftNodes {
-		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[L, R]]{
+		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[L, R], float64]{
 			VertexFrom: source,
 			VertexTo:   leftNode,
 			Weight:     1,
@@ -72,7 +72,7 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 			connect, weight := weights(leftNode.Node.LeftValue, rightNode.Node.RightValue)
 
 			if connect {
-				newEdge := graph.WeightedDirectedEdge[MatchNode[L, R]]{
+				newEdge := graph.WeightedDirectedEdge[MatchNode[L, R], float64]{
 					VertexFrom: leftNode,
 					VertexTo:   rightNode,
 					Weight:     weight,
@@ -84,7 +84,7 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 	}
 
 	for _, slot := range rightNodes {
-		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[L, R]]{
+		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[L, R], float64]{
 			VertexFrom: slot,
 			VertexTo:   sink,
 			Weight:     1,
@@ -96,8 +96,8 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 	allVertices = append(allVertices, source)
 	allVertices = append(allVertices, sink)
 
-	net = network.WeigthedNetwork[MatchNode[L, R]]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[MatchNode[L, R]]{
+	net = network.WeigthedNetwork[MatchNode[L, R], float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[MatchNode[L, R], float64]{
 			Vertices: allVertices,
 			Edges:    allEdges,
 		},
@@ -108,9 +108,14 @@ func (m MatchingProblem[L, R]) ConstructNetworkFromProblem(weights func(leftNode
 	return
 }
 
-func (m MatchingProblem[L, R]) InterpretNetworkFlow(flow map[*graph.WeightedDirectedEdge[MatchNode[L, R]]]float64, source graph.Vertex[MatchNode[L, R]], sink graph.Vertex[MatchNode[L, R]]) (matching []MatchingEdge[L, R], err error) {
-	matchingWeightedEdges := util.FilterMapBoth(flow, func(wde *graph.WeightedDirectedEdge[MatchNode[L, R]], f float64) bool {
-		if f == 0 {
+/*
+Reads the matching off a flow in the network built by ConstructNetworkFromProblem. Edges whose flow is
+zero up to the tolerance configured by opts (see network.WithTolerance) are not part of the matching.
+*/
+func (m MatchingProblem[L, R]) InterpretNetworkFlow(flow map[*graph.WeightedDirectedEdge[MatchNode[L, R], float64]]float64, source graph.Vertex[MatchNode[L, R]], sink graph.Vertex[MatchNode[L, R]], opts ...network.Option) (matching []MatchingEdge[L, R], err error) {
+	tol := network.ToleranceOf(opts...)
+	matchingWeightedEdges := util.FilterMapBoth(flow, func(wde *graph.WeightedDirectedEdge[MatchNode[L, R], float64], f float64) bool {
+		if tol.IsZero(f) {
 			return false
 		}
 		if wde.VertexFrom.Node == source.Node || wde.VertexTo.Node == sink.Node {
@@ -136,7 +141,7 @@ func (m MatchingProblem[L, R]) InterpretNetworkFlow(flow map[*graph.WeightedDire
 }
 
 // Shuffles the order of the given edges iteration times to get different results
-func (m MatchingProblem[L, R]) SolveMany(iterations int, weights func(leftNode L, rightNode R) (connect bool, weights float64), capacities func(rightNode R) (capacity float64)) (matchings [][]MatchingEdge[L, R], err error) {
+func (m MatchingProblem[L, R]) SolveMany(iterations int, weights func(leftNode L, rightNode R) (connect bool, weights float64), capacities func(rightNode R) (capacity float64), opts ...network.Option) (matchings [][]MatchingEdge[L, R], err error) {
 	network, source, sink := m.ConstructNetworkFromProblem(weights, capacities)
 
 	for i := 0; i < iterations; i++ {
@@ -144,25 +149,23 @@ func (m MatchingProblem[L, R]) SolveMany(iterations int, weights func(leftNode L
 		rand.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
 		network.Edges = edges
 
-		flow := network.MinCostMaxFlow()
+		flow := network.MinCostMaxFlow(opts...)
 
-		matching, errThis := m.InterpretNetworkFlow(flow, source, sink)
+		matching, errThis := m.InterpretNetworkFlow(flow, source, sink, opts...)
 		matchings = append(matchings, matching)
 
 		if errThis != nil {
 			err = errThis
 		}
-
-		fmt.Printf("err: %v\n", err)
 	}
 
 	return matchings, err
 }
 
-func (m MatchingProblem[L, R]) Solve(weights func(leftNode L, rightNode R) (connect bool, weight float64), capacities func(rightNode R) (capacity float64)) (matching []MatchingEdge[L, R], err error) {
+func (m MatchingProblem[L, R]) Solve(weights func(leftNode L, rightNode R) (connect bool, weight float64), capacities func(rightNode R) (capacity float64), opts ...network.Option) (matching []MatchingEdge[L, R], err error) {
 	network, source, sink := m.ConstructNetworkFromProblem(weights, capacities)
 
-	flow := network.MinCostMaxFlow()
+	flow := network.MinCostMaxFlow(opts...)
 
-	return m.InterpretNetworkFlow(flow, source, sink)
+	return m.InterpretNetworkFlow(flow, source, sink, opts...)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/JonasBernard/min-cost-max-flow/matching"
 	"github.com/JonasBernard/min-cost-max-flow/network"
 	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
 )
 
 type MatchNode[L any, R any] struct {
@@ -107,7 +108,7 @@ func TestMatching(t *testing.T) {
 	})
 
 	// the capacity cannot be known beforehand because it depends on what the choices are
-	allEdges := make([]*graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]], 0, 4*len(childNodes)+len(workshopNodes))
+	allEdges := make([]*graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64], 0, 4*len(childNodes)+len(workshopNodes))
 
 	source := graph.V(&MatchNode[Child, WorkshopSlot]{
 		Name: "S", IsSource: true,
@@ -118,7 +119,7 @@ func TestMatching(t *testing.T) {
 	})
 
 	for _, childNode := range childNodes {
-		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 			VertexFrom: source,
 			VertexTo:   childNode,
 			Weight:     1,
@@ -147,7 +148,7 @@ func TestMatching(t *testing.T) {
 		})
 
 		for _, slot := range correspondingNodesW1 {
-			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 				VertexFrom: childNode,
 				VertexTo:   slot,
 				Weight:     1,
@@ -157,7 +158,7 @@ func TestMatching(t *testing.T) {
 		}
 
 		for _, slot := range correspondingNodesW2 {
-			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 				VertexFrom: childNode,
 				VertexTo:   slot,
 				Weight:     2,
@@ -167,7 +168,7 @@ func TestMatching(t *testing.T) {
 		}
 
 		for _, slot := range correspondingNodesW3 {
-			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 				VertexFrom: childNode,
 				VertexTo:   slot,
 				Weight:     4,
@@ -177,7 +178,7 @@ func TestMatching(t *testing.T) {
 		}
 
 		for _, slot := range allOtherSlots {
-			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+			newEdge := graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 				VertexFrom: childNode,
 				VertexTo:   slot,
 				Weight:     10,
@@ -188,7 +189,7 @@ func TestMatching(t *testing.T) {
 	}
 
 	for _, slot := range workshopNodes {
-		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]]{
+		allEdges = append(allEdges, &graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64]{
 			VertexFrom: slot,
 			VertexTo:   sink,
 			Weight:     0,
@@ -200,8 +201,8 @@ func TestMatching(t *testing.T) {
 	allVertices = append(allVertices, source)
 	allVertices = append(allVertices, sink)
 
-	network := network.WeigthedNetwork[MatchNode[Child, WorkshopSlot]]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[MatchNode[Child, WorkshopSlot]]{
+	network := network.WeigthedNetwork[MatchNode[Child, WorkshopSlot], float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[MatchNode[Child, WorkshopSlot], float64]{
 			Vertices: allVertices,
 			Edges:    allEdges,
 		},
@@ -217,7 +218,7 @@ func TestMatching(t *testing.T) {
 
 	// interpret flow again
 
-	matchingEdges := util.FilterMapBoth(flow, func(wde *graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]], f float64) bool {
+	matchingEdges := util.FilterMapBoth(flow, func(wde *graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64], f float64) bool {
 		if f == 0 {
 			return false
 		}
@@ -244,7 +245,7 @@ func TestMatching(t *testing.T) {
 	for _, w := range workshops {
 		fmt.Println()
 		fmt.Printf("Kids of workshop %v (max %v):\n", w.Name, w.Capacity)
-		for e := range util.FilterMapBoth(matchingEdges, func(wde *graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot]], f float64) bool {
+		for e := range util.FilterMapBoth(matchingEdges, func(wde *graph.WeightedDirectedEdge[MatchNode[Child, WorkshopSlot], float64], f float64) bool {
 			return wde.VertexTo.Node.RightValue.Workshop == w
 		}) {
 			fmt.Printf("%v\n", e.VertexFrom.Node.LeftValue.Name)
@@ -348,3 +349,34 @@ func TestMatching2(t *testing.T) {
 		}
 	}
 }
+
+func TestInterpretFlowWithRoundingErrors(t *testing.T) {
+	problem := matching.MatchingProblem[Child, WorkshopSlot]{
+		Lefts:  []Child{{"Mia", "Tanz", "Tuch", "Akro"}},
+		Rights: []WorkshopSlot{{Workshop{"Tanz", 1}, 1}, {Workshop{"Tuch", 1}, 1}},
+	}
+
+	net, source, sink := problem.ConstructNetworkFromProblem(func(c Child, w WorkshopSlot) (bool, float64) {
+		return true, 1
+	}, func(w WorkshopSlot) float64 {
+		return 1
+	})
+
+	flow := make(map[*graph.WeightedDirectedEdge[matching.MatchNode[Child, WorkshopSlot], float64]]float64)
+	for _, e := range net.Edges {
+		if e.VertexTo.Node.Name == "Tanz (slot 1)" || e.VertexFrom.Node.Name == "Tanz (slot 1)" || e.VertexFrom.Node.IsSource {
+			flow[e] = 1
+		} else {
+			// left over by an augmentation in floating point arithmetic
+			flow[e] = 1e-17
+		}
+	}
+
+	matchingEdges, err := problem.InterpretNetworkFlow(flow, source, sink)
+	assert.NoError(t, err)
+	assert.Len(t, matchingEdges, 1)
+	assert.Equal(t, "Tanz", matchingEdges[0].Right.Workshop.Name)
+
+	matchingEdges, _ = problem.InterpretNetworkFlow(flow, source, sink, network.WithTolerance(util.Tolerance{}))
+	assert.Len(t, matchingEdges, 2)
+}
@@ -1,7 +1,10 @@
 package minimax
 
 import (
+	"context"
 	"math"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
 const (
@@ -26,8 +29,18 @@ func MinimaxMaximize[T GameState[T]](state GameState[T]) (bestMove GameState[T])
 }
 
 func MinimaxRecurse[T GameState[T]](state GameState[T], isMaximizing bool) (GameState[T], int) {
+	bestMove, bestVal, _ := MinimaxRecurseContext[T](context.Background(), state, isMaximizing)
+	return bestMove, bestVal
+}
+
+/*
+Same as MinimaxRecurse, but checks ctx before expanding a state and gives up once it is done.
+In that case the best move among the moves that were evaluated completely is returned
+(or the state itself if there is none yet) together with an error that matches util.ErrInterrupted.
+*/
+func MinimaxRecurseContext[T GameState[T]](ctx context.Context, state GameState[T], isMaximizing bool) (GameState[T], int, error) {
 	if state.IsTerminal() {
-		return nil, state.ValueOfTerminal()
+		return nil, state.ValueOfTerminal(), nil
 	}
 
 	bestVal := math.MaxInt
@@ -35,13 +48,21 @@ func MinimaxRecurse[T GameState[T]](state GameState[T], isMaximizing bool) (Game
 		bestVal = math.MinInt
 	}
 	bestMove := state
+
+	if err := util.Interrupted(ctx); err != nil {
+		return bestMove, bestVal, err
+	}
+
 	for _, move := range state.GeneratePossibleMoves(!isMaximizing) {
-		_, val:= MinimaxRecurse[T](move, !isMaximizing)
+		_, val, err := MinimaxRecurseContext[T](ctx, move, !isMaximizing)
+		if err != nil {
+			// the value of this move is incomplete, so it must not compete with the others
+			return bestMove, bestVal, err
+		}
 		if (val > bestVal && isMaximizing) || (val < bestVal && !isMaximizing) {
 			bestVal = val
 			bestMove = move
 		}
 	}
-	return bestMove, bestVal
+	return bestMove, bestVal, nil
 }
-
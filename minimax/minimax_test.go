@@ -1,10 +1,13 @@
 package minimax_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/minimax"
+	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
 type TicTacToe struct {
@@ -117,3 +120,27 @@ func TestTicTacToe(t *testing.T) {
 
 	fmt.Println(minimax.MinimaxMaximize[TicTacToe](game))
 }
+
+func TestTicTacToeCancelled(t *testing.T) {
+	X := 1
+	O := 2
+
+	gameboard := [][]int{
+		{X, 0, 0},
+		{0, 0, O},
+		{0, O, O},
+	}
+
+	game := TicTacToe{gameboard: gameboard}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bestMove, _, err := minimax.MinimaxRecurseContext[TicTacToe](ctx, game, true)
+	if !errors.Is(err, util.ErrInterrupted) {
+		t.Fatalf("expected the search to be interrupted, got %v", err)
+	}
+	if fmt.Sprint(bestMove) != fmt.Sprint(game) {
+		t.Errorf("expected no move to be chosen, got %v", bestMove)
+	}
+}
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+// A path from the source to the sink or a cycle together with the flow it carries.
+type FlowPath[T graph.Node, N util.Number] struct {
+	Path   graph.Path[T, N]
+	Amount N
+}
+
+func (p FlowPath[T, N]) String() string {
+	return fmt.Sprintf("%v units go %v", p.Amount, p.Path)
+}
+
+/*
+Decomposes a flow into paths from the source to the sink and cycles, each with the amount of flow
+it carries, such that the flow on every edge is the sum of the amounts of the paths and cycles
+through it. The amounts of the paths add up to the value of the flow. There are at most as many
+paths and cycles together as edges with flow, since each one empties at least one edge.
+
+Paths are found by following edges with remaining flow from the source. Once a walk returns to a
+vertex it has already visited, the cycle it closed is split off instead. Whatever is left after
+the source has no outgoing flow anymore is a circulation, which is split into cycles the same way.
+Flows of the acyclic networks MinCostMaxFlow is made for have no cycles.
+
+The flow is checked with ValidateFlow first, an invalid flow returns the error of its report.
+*/
+func (n WeigthedNetwork[T, N]) DecomposeFlow(flow map[*graph.WeightedDirectedEdge[T, N]]N, opts ...Option) (paths []FlowPath[T, N], cycles []FlowPath[T, N], err error) {
+	tol := collectOptions(opts).tolerance
+	if err := n.ValidateFlow(flow, opts...).Err(); err != nil {
+		return nil, nil, err
+	}
+
+	remaining := make(map[*graph.WeightedDirectedEdge[T, N]]N, len(n.Edges))
+	outgoing := make(map[*T][]*graph.WeightedDirectedEdge[T, N], len(n.Vertices))
+	for _, e := range n.Edges {
+		if util.SignWithin(tol, flow[e]) > 0 {
+			remaining[e] = flow[e]
+			outgoing[e.VertexFrom.Node] = append(outgoing[e.VertexFrom.Node], e)
+		}
+	}
+	// the first outgoing edge of v that still has flow, dropping the empty ones before it
+	next := func(v *T) *graph.WeightedDirectedEdge[T, N] {
+		for len(outgoing[v]) > 0 {
+			if e := outgoing[v][0]; util.SignWithin(tol, remaining[e]) > 0 {
+				return e
+			}
+			outgoing[v] = outgoing[v][1:]
+		}
+		return nil
+	}
+	// removes the bottleneck of the edges from the remaining flow and returns them as flow path
+	split := func(edges []*graph.WeightedDirectedEdge[T, N]) FlowPath[T, N] {
+		amount := remaining[edges[0]]
+		for _, e := range edges {
+			amount = min(amount, remaining[e])
+		}
+		path := graph.Path[T, N]{Vertices: []graph.Vertex[T]{edges[0].VertexFrom}}
+		for _, e := range edges {
+			remaining[e] -= amount
+			if util.SignWithin(tol, remaining[e]) <= 0 {
+				remaining[e] = 0
+			}
+			path.Vertices = append(path.Vertices, e.VertexTo)
+		}
+		path.Edges = edges
+		if edges[0].VertexFrom.Node == edges[len(edges)-1].VertexTo.Node {
+			// a cycle lists its first vertex only once
+			path.Vertices = path.Vertices[:len(path.Vertices)-1]
+		}
+		return FlowPath[T, N]{Path: path, Amount: amount}
+	}
+
+	// walks along edges with remaining flow from start until it reaches the sink (if toSink) or closes a cycle and splits off what it found
+	walk := func(start *T, toSink bool) error {
+		var edges []*graph.WeightedDirectedEdge[T, N]
+		// the position of every vertex on the walk
+		visited := map[*T]int{start: 0}
+		v := start
+		for {
+			e := next(v)
+			if e == nil {
+				return fmt.Errorf("%w: flow does not continue at %v", ErrInvalidFlow, graph.V(v))
+			}
+			edges = append(edges, e)
+			v = e.VertexTo.Node
+			if toSink && v == n.Sink.Node {
+				paths = append(paths, split(edges))
+				return nil
+			}
+			if i, ok := visited[v]; ok {
+				cycles = append(cycles, split(edges[i:]))
+				return nil
+			}
+			visited[v] = len(edges)
+		}
+	}
+
+	for next(n.Source.Node) != nil {
+		if err := walk(n.Source.Node, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, v := range n.Vertices {
+		for next(v.Node) != nil {
+			if err := walk(v.Node, false); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return paths, cycles, nil
+}
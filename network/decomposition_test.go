@@ -0,0 +1,113 @@
+package network_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/network"
+)
+
+// Checks that paths and cycles add up to the flow and have the right shape.
+func checkDecomposition(test *testing.T, net network.WeigthedNetwork[TestNode, int64], flow map[*graph.WeightedDirectedEdge[TestNode, int64]]int64, paths []network.FlowPath[TestNode, int64], cycles []network.FlowPath[TestNode, int64]) {
+	test.Helper()
+	sum := map[*graph.WeightedDirectedEdge[TestNode, int64]]int64{}
+	var value int64
+	for _, p := range append(paths, cycles...) {
+		if p.Amount <= 0 {
+			test.Errorf("%v does not carry flow", p)
+		}
+		for i, e := range p.Path.Edges {
+			if i > 0 && p.Path.Edges[i-1].VertexTo.Node != e.VertexFrom.Node {
+				test.Errorf("%v is not connected", p)
+			}
+			sum[e] += p.Amount
+		}
+	}
+	for _, p := range paths {
+		edges := p.Path.Edges
+		if edges[0].VertexFrom.Node != net.Source.Node || edges[len(edges)-1].VertexTo.Node != net.Sink.Node {
+			test.Errorf("%v does not lead from the source to the sink", p)
+		}
+		if len(p.Path.Vertices) != len(edges)+1 {
+			test.Errorf("%v has %v vertices", p, len(p.Path.Vertices))
+		}
+		value += p.Amount
+	}
+	for _, c := range cycles {
+		edges := c.Path.Edges
+		if edges[0].VertexFrom.Node != edges[len(edges)-1].VertexTo.Node || len(c.Path.Vertices) != len(edges) {
+			test.Errorf("%v is not a cycle", c)
+		}
+	}
+	for _, e := range net.Edges {
+		if sum[e] != flow[e] {
+			test.Errorf("%v carries %v, but the decomposition %v", e, flow[e], sum[e])
+		}
+	}
+	if report := net.ValidateFlow(flow); value != report.Value {
+		test.Errorf("expected the paths to carry %v, got %v", report.Value, value)
+	}
+}
+
+func TestDecomposeFlow(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	b := graph.V(&TestNode{Name: "B"})
+	c := graph.V(&TestNode{Name: "C"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	sa := graph.E[TestNode, int64](s, a, 1, 5)
+	ab := graph.E[TestNode, int64](a, b, 1, 5)
+	bc := graph.E[TestNode, int64](b, c, 1, 5)
+	ca := graph.E[TestNode, int64](c, a, 1, 5)
+	bt := graph.E[TestNode, int64](b, t, 1, 5)
+	at := graph.E[TestNode, int64](a, t, 1, 5)
+	net := network.WeigthedNetwork[TestNode, int64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, int64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, c, s, t},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, int64]{sa, ab, bc, ca, bt, at},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	// 3 units along S, A, B, T and 1 along S, A, T, with 2 units circling through A, B, C
+	flow := map[*graph.WeightedDirectedEdge[TestNode, int64]]int64{sa: 4, ab: 5, bc: 2, ca: 2, bt: 3, at: 1}
+	paths, cycles, err := net.DecomposeFlow(flow)
+	if err != nil {
+		test.Fatalf("unexpected error: %v", err)
+	}
+	checkDecomposition(test, net, flow, paths, cycles)
+	if len(paths) != 2 || len(cycles) != 1 {
+		test.Fatalf("expected two paths and a cycle, got %v and %v", paths, cycles)
+	}
+	if cycles[0].Amount != 2 || cycles[0].Path.String() != "A -> B -> C -> A" {
+		test.Errorf("unexpected cycle %v", cycles[0])
+	}
+	if paths[0].String() != "3 units go S -> A -> B -> T" {
+		test.Errorf("unexpected path %v", paths[0])
+	}
+
+	flow[at] = 2
+	if _, _, err := net.DecomposeFlow(flow); !errors.Is(err, network.ErrInvalidFlow) {
+		test.Errorf("expected an invalid flow, got %v", err)
+	}
+}
+
+func TestDecomposeFlowOfRandomNetworks(test *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for range 100 {
+		net := randomNetwork(r, 2+r.Intn(7))
+		flow := net.MinCostMaxFlow()
+		paths, cycles, err := net.DecomposeFlow(flow)
+		if err != nil {
+			test.Fatalf("unexpected error: %v", err)
+		}
+		if len(cycles) != 0 {
+			test.Errorf("expected no cycles in an acyclic network, got %v", cycles)
+		}
+		checkDecomposition(test, net, flow, paths, cycles)
+	}
+}
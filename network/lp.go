@@ -0,0 +1,159 @@
+package network
+
+import (
+	"context"
+	"math"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+Solves the min cost flow problem that sends exactly value units from the source to the sink as a
+linear program with lp.BoundedSimplex: one variable per edge with 0 <= f(e) <= capacity, whose
+cost is minimized subject to flow conservation at every vertex. Edges with capacity
+util.Infinity[N]() are not bounded.
+
+Returns the flow per edge and node potentials from the dual solution of the conservation rows,
+with potential zero at the source. They prove optimality: the reduced cost
+weight(e) + potential(from) - potential(to) is non-negative on every edge that is not saturated
+and non-positive on every edge that carries flow, which are the arcs of the residual graph.
+If N is int64, both are rounded to integers, which they are up to rounding errors since the
+constraint matrix is totally unimodular. Fails with lp.ErrInfeasible if the value cannot be sent.
+
+This is independent of MinCostMaxFlow, so both can check each other.
+*/
+func (n WeigthedNetwork[T, N]) MinCostFlowLP(value N, opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	return n.MinCostFlowLPContext(context.Background(), value, opts...)
+}
+
+// Same as MinCostFlowLP, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func (n WeigthedNetwork[T, N]) MinCostFlowLPContext(ctx context.Context, value N, opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	p := n.flowLP(opts)
+	p.lower[p.value], p.upper[p.value] = float64(value), float64(value)
+	return p.minCost(ctx)
+}
+
+/*
+Same as MinCostMaxFlow, but solved as two linear programs: the first maximizes the flow value, the
+second minimizes the cost of flows of that value, see MinCostFlowLP for the potentials. Fails with
+lp.ErrUnbounded if edges of infinite capacity connect the source to the sink.
+*/
+func (n WeigthedNetwork[T, N]) MinCostMaxFlowLP(opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	return n.MinCostMaxFlowLPContext(context.Background(), opts...)
+}
+
+// Same as MinCostMaxFlowLP, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func (n WeigthedNetwork[T, N]) MinCostMaxFlowLPContext(ctx context.Context, opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	p := n.flowLP(opts)
+	c := make([]float64, len(p.c))
+	c[p.value] = 1
+	_, _, maxValue, err := lp.BoundedSimplexContext(ctx, c, p.A, p.b, p.lower, p.upper, p.opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if util.IsIntegral[N]() {
+		maxValue = math.Round(maxValue)
+	}
+	p.lower[p.value], p.upper[p.value] = maxValue, maxValue
+	return p.minCost(ctx)
+}
+
+/*
+The min cost flow problem as max c@x s.t. A@x <= b, lower <= x <= upper for lp.BoundedSimplex, where
+x holds the flow on every edge followed by the flow value and each vertex has the two rows
+out - in <= supply and in - out <= -supply, with supply = value at the source and -value at the sink.
+*/
+type flowLP[T graph.Node, N util.Number] struct {
+	n     WeigthedNetwork[T, N]
+	opts  []lp.Option
+	tol   util.Tolerance
+	c     []float64
+	A     [][]float64
+	b     []float64
+	lower []float64
+	upper []float64
+	// the column of the flow value
+	value int
+}
+
+func (n WeigthedNetwork[T, N]) flowLP(opts []Option) *flowLP[T, N] {
+	o := collectOptions(opts)
+	p := &flowLP[T, N]{n: n, tol: o.tolerance, value: len(n.Edges)}
+	p.opts = []lp.Option{lp.WithTolerance(o.tolerance)}
+	if o.observer != nil {
+		p.opts = append(p.opts, lp.WithObserver(o.observer))
+	}
+
+	columns := len(n.Edges) + 1
+	rows := make(map[*T]int, len(n.Vertices))
+	for i, v := range n.Vertices {
+		rows[v.Node] = i
+	}
+	p.A = make([][]float64, 2*len(n.Vertices))
+	for i := range p.A {
+		p.A[i] = make([]float64, columns)
+	}
+	p.b = make([]float64, len(p.A))
+	p.c = make([]float64, columns)
+	p.lower = make([]float64, columns)
+	p.upper = make([]float64, columns)
+
+	for j, e := range n.Edges {
+		from, to := rows[e.VertexFrom.Node], rows[e.VertexTo.Node]
+		p.A[2*from][j] += 1
+		p.A[2*from+1][j] -= 1
+		p.A[2*to][j] -= 1
+		p.A[2*to+1][j] += 1
+		p.c[j] = -float64(e.Weight)
+		p.upper[j] = float64(e.Capacity)
+		if e.Capacity == util.Infinity[N]() {
+			p.upper[j] = math.Inf(1)
+		}
+	}
+	// out - in - value <= 0 at the source and out - in + value <= 0 at the sink
+	source, sink := rows[n.Source.Node], rows[n.Sink.Node]
+	p.A[2*source][p.value] -= 1
+	p.A[2*source+1][p.value] += 1
+	p.A[2*sink][p.value] += 1
+	p.A[2*sink+1][p.value] -= 1
+	p.upper[p.value] = math.Inf(1)
+	return p
+}
+
+// Minimizes the cost with the flow value fixed by the bounds of its column.
+func (p *flowLP[T, N]) minCost(ctx context.Context) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	x, y, _, err := lp.BoundedSimplexContext(ctx, p.c, p.A, p.b, p.lower, p.upper, p.opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flow = make(map[*graph.WeightedDirectedEdge[T, N]]N, len(p.n.Edges))
+	for j, e := range p.n.Edges {
+		flow[e] = p.number(x[j])
+	}
+	// the dual of the equation out - in = supply is the difference of the duals of its two rows
+	source := 0.0
+	for i, v := range p.n.Vertices {
+		if v.Node == p.n.Source.Node {
+			source = y[2*i] - y[2*i+1]
+		}
+	}
+	potentials = make(map[graph.Vertex[T]]N, len(p.n.Vertices))
+	for i, v := range p.n.Vertices {
+		potentials[v] = p.number(y[2*i] - y[2*i+1] - source)
+	}
+	return flow, potentials, nil
+}
+
+// Converts a value of the solution to N, rounding for integer types and snapping to zero within the tolerance.
+func (p *flowLP[T, N]) number(v float64) N {
+	if util.IsIntegral[N]() {
+		return N(math.Round(v))
+	}
+	if p.tol.IsZero(v) {
+		return 0
+	}
+	return N(v)
+}
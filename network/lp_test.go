@@ -0,0 +1,132 @@
+package network_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/lp"
+	"github.com/JonasBernard/min-cost-max-flow/network"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+// A random acyclic network with non-negative costs, source 0 and sink n-1.
+func randomNetwork(r *rand.Rand, n int) network.WeigthedNetwork[TestNode, int64] {
+	vertices := make([]graph.Vertex[TestNode], n)
+	for i := range vertices {
+		vertices[i] = graph.V(&TestNode{Name: fmt.Sprint(i)})
+	}
+	var edges []*graph.WeightedDirectedEdge[TestNode, int64]
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			if r.Intn(3) == 0 {
+				edges = append(edges, graph.E(vertices[i], vertices[j], r.Int63n(10), 1+r.Int63n(8)))
+			}
+		}
+	}
+	return network.WeigthedNetwork[TestNode, int64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, int64]{Vertices: vertices, Edges: edges},
+		Source:                vertices[0],
+		Sink:                  vertices[n-1],
+	}
+}
+
+func valueAndCost[N util.Number](net network.WeigthedNetwork[TestNode, N], flow map[*graph.WeightedDirectedEdge[TestNode, N]]N) (value N, cost N) {
+	for _, e := range net.Edges {
+		if e.VertexFrom.Node == net.Source.Node {
+			value += flow[e]
+		}
+		if e.VertexTo.Node == net.Source.Node {
+			value -= flow[e]
+		}
+		cost += flow[e] * e.Weight
+	}
+	return
+}
+
+// Checks that the reduced costs are non-negative on all arcs of the residual graph.
+func checkPotentials[N util.Number](test *testing.T, net network.WeigthedNetwork[TestNode, N], flow map[*graph.WeightedDirectedEdge[TestNode, N]]N, potentials map[graph.Vertex[TestNode]]N) {
+	test.Helper()
+	for _, e := range net.ResidualGraph(flow).Edges {
+		reduced := float64(e.Weight + potentials[e.VertexFrom] - potentials[e.VertexTo])
+		if reduced < -1e-9 {
+			test.Errorf("residual arc %v has negative reduced cost %v", e, reduced)
+		}
+	}
+}
+
+func TestMinCostMaxFlowLP(test *testing.T) {
+	a := graph.V(&TestNode{Name: "2"})
+	b := graph.V(&TestNode{Name: "3"})
+	s := graph.V(&TestNode{Name: "1"})
+	t := graph.V(&TestNode{Name: "4"})
+
+	net := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, s, t},
+			Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
+				graph.E[TestNode, float64](s, a, 1, 1),
+				graph.E[TestNode, float64](s, b, 5, 3),
+				graph.E[TestNode, float64](a, b, 1, 2),
+				graph.E[TestNode, float64](a, t, 4, 1),
+				graph.E[TestNode, float64](b, t, 2, 3),
+			},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	flow, potentials, err := net.MinCostMaxFlowLP()
+	if err != nil {
+		test.Fatalf("unexpected error: %v", err)
+	}
+	value, cost := valueAndCost(net, flow)
+	expectedValue, expectedCost := valueAndCost(net, net.MinCostMaxFlow())
+	if math.Abs(value-expectedValue) > 1e-9 || math.Abs(cost-expectedCost) > 1e-9 {
+		test.Errorf("expected value %v at cost %v, got %v at cost %v", expectedValue, expectedCost, value, cost)
+	}
+	if potentials[s] != 0 {
+		test.Errorf("expected potential zero at the source, got %v", potentials[s])
+	}
+	checkPotentials(test, net, flow, potentials)
+
+	// one unit takes the cheapest path s, a, b, t
+	flow, potentials, err = net.MinCostFlowLP(1)
+	if err != nil {
+		test.Fatalf("unexpected error: %v", err)
+	}
+	if value, cost := valueAndCost(net, flow); value != 1 || cost != 4 {
+		test.Errorf("expected one unit at cost 4, got %v at cost %v", value, cost)
+	}
+	checkPotentials(test, net, flow, potentials)
+
+	if _, _, err := net.MinCostFlowLP(5); !errors.Is(err, lp.ErrInfeasible) {
+		test.Errorf("expected five units to be infeasible, got %v", err)
+	}
+
+	net.Edges = append(net.Edges, graph.E(s, t, 1, util.Infinity[float64]()))
+	if _, _, err := net.MinCostMaxFlowLP(); !errors.Is(err, lp.ErrUnbounded) {
+		test.Errorf("expected an unbounded flow, got %v", err)
+	}
+}
+
+func TestMinCostMaxFlowLPAgreesWithMinCostMaxFlow(test *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for k := range 100 {
+		net := randomNetwork(r, 2+r.Intn(7))
+
+		flow, potentials, err := net.MinCostMaxFlowLP()
+		if err != nil {
+			test.Fatalf("network %v: unexpected error: %v", k, err)
+		}
+		value, cost := valueAndCost(net, flow)
+		expectedValue, expectedCost := valueAndCost(net, net.MinCostMaxFlow())
+		if value != expectedValue || cost != expectedCost {
+			test.Errorf("network %v: expected value %v at cost %v, got %v at cost %v", k, expectedValue, expectedCost, value, cost)
+		}
+		checkPotentials(test, net, flow, potentials)
+	}
+}
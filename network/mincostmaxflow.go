@@ -1,6 +1,9 @@
 package network
 
 import (
+	"context"
+	"slices"
+
 	"github.com/JonasBernard/min-cost-max-flow/graph"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 )
@@ -16,11 +19,18 @@ To see where the algorithm comes from, see https://sboyles.github.io/teaching/ce
 
 It is not checked if adding the reverse arcs does not create a multi-graph.
 */
-func (n WeigthedNetwork[T]) ResidualGraph(flow map[*graph.WeightedDirectedEdge[T]]float64) (residual WeigthedNetwork[T]) {
-	residual = WeigthedNetwork[T]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[T]{
+func (n WeigthedNetwork[T, N]) ResidualGraph(flow map[*graph.WeightedDirectedEdge[T, N]]N) (residual WeigthedNetwork[T, N]) {
+	return n.ResidualGraphWithin(flow, util.DefaultTolerance)
+}
+
+/*
+Same as ResidualGraph, but edges whose residual capacity is zero up to the given tolerance are left out.
+*/
+func (n WeigthedNetwork[T, N]) ResidualGraphWithin(flow map[*graph.WeightedDirectedEdge[T, N]]N, tol util.Tolerance) (residual WeigthedNetwork[T, N]) {
+	residual = WeigthedNetwork[T, N]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[T, N]{
 			Vertices: n.Vertices,
-			Edges:    make([]*graph.WeightedDirectedEdge[T], 0),
+			Edges:    make([]*graph.WeightedDirectedEdge[T, N], 0),
 		},
 		Source: n.Source,
 		Sink:   n.Sink,
@@ -33,9 +43,9 @@ func (n WeigthedNetwork[T]) ResidualGraph(flow map[*graph.WeightedDirectedEdge[T
 
 		// fmt.Printf("Considering edge from %v to %v. Capacity: %v. Flow: %v\n", e.VertexFrom, e.VertexTo, e.Capacity, flowHere)
 
-		if e.Capacity > flowHere {
+		if util.CompareWithin(tol, e.Capacity, flowHere) > 0 {
 			// fmt.Printf("adding forward arc for %v and %v of capacity %v\n", e.VertexFrom.Node, e.VertexTo.Node, e.Capacity-flowHere)
-			forwardArc := graph.WeightedDirectedEdge[T]{
+			forwardArc := graph.WeightedDirectedEdge[T, N]{
 				VertexFrom:   e.VertexFrom,
 				VertexTo:     e.VertexTo,
 				Weight:       e.Weight,
@@ -46,9 +56,9 @@ func (n WeigthedNetwork[T]) ResidualGraph(flow map[*graph.WeightedDirectedEdge[T
 			residual.Edges = append(residual.Edges, &forwardArc)
 		}
 
-		if flowHere > 0 {
+		if util.SignWithin(tol, flowHere) > 0 {
 			// fmt.Printf("adding reverse arc for %v and %v of capacity %v\n", e.VertexFrom.Node, e.VertexTo.Node, flowHere)
-			reverseArc := graph.WeightedDirectedEdge[T]{
+			reverseArc := graph.WeightedDirectedEdge[T, N]{
 				VertexFrom:   e.VertexTo,
 				VertexTo:     e.VertexFrom,
 				Weight:       -e.Weight,
@@ -67,10 +77,9 @@ func (n WeigthedNetwork[T]) ResidualGraph(flow map[*graph.WeightedDirectedEdge[T
 	return
 }
 
-func (_ WeigthedNetwork[T]) AugmentFlow(flow map[*graph.WeightedDirectedEdge[T]]float64, path graph.WeigthedDirectedGraph[T]) (newFlow map[*graph.WeightedDirectedEdge[T]]float64) {
+func (_ WeigthedNetwork[T, N]) AugmentFlow(flow map[*graph.WeightedDirectedEdge[T, N]]N, path graph.WeigthedDirectedGraph[T, N]) (newFlow map[*graph.WeightedDirectedEdge[T, N]]N) {
 	newFlow = flow
-	pathCapacities := util.MapSlice(path.Edges, func(edge **graph.WeightedDirectedEdge[T]) float64 { return (*edge).Capacity })
-	bottleneck := util.MinSlice(pathCapacities)
+	bottleneck := bottleneckOf(path)
 
 	// fmt.Printf("Augmenting by bottleneck value  %v\n\n", bottleneck)
 
@@ -87,19 +96,48 @@ func (_ WeigthedNetwork[T]) AugmentFlow(flow map[*graph.WeightedDirectedEdge[T]]
 	return
 }
 
+// The smallest capacity along the given path.
+func bottleneckOf[T graph.Node, N util.Number](path graph.WeigthedDirectedGraph[T, N]) N {
+	return slices.Min(util.MapSlice(path.Edges, func(edge **graph.WeightedDirectedEdge[T, N]) N { return (*edge).Capacity }))
+}
+
 /*
 See algorithm B on page 257 in
 https://dl.acm.org/doi/10.1145/321694.321699
+
+If N is int64 all computations are exact and, since all capacities are integral,
+so is the returned flow.
+*/
+func (n WeigthedNetwork[T, N]) MinCostMaxFlow(opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N) {
+	flow, _ = n.MinCostMaxFlowContext(context.Background(), opts...)
+	return
+}
+
+/*
+Same as MinCostMaxFlow, but checks ctx before every augmentation and stops once it is done.
+The flow returned in that case is the one built up so far: it is feasible and has minimal cost
+among all flows of the same value, but its value is not necessarily maximal.
+The error then matches util.ErrInterrupted.
 */
-func (n WeigthedNetwork[T]) MinCostMaxFlow() (flow map[*graph.WeightedDirectedEdge[T]]float64) {
-	flow = make(map[*graph.WeightedDirectedEdge[T]]float64, 0)
+func (n WeigthedNetwork[T, N]) MinCostMaxFlowContext(ctx context.Context, opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, err error) {
+	o := collectOptions(opts)
+
+	flow = make(map[*graph.WeightedDirectedEdge[T, N]]N, 0)
 	for _, e := range n.Edges {
 		flow[e] = 0
 	}
 
+	iter := 0
+	var value, cost N
 	for {
-		residual := n.ResidualGraph(flow)
-		distances := residual.BellmanFordMoore(residual.Source)
+		iter++
+
+		if err = util.Interrupted(ctx); err != nil {
+			return
+		}
+
+		residual := n.ResidualGraphWithin(flow, o.tolerance)
+		distances := residual.BellmanFordMooreWithin(residual.Source, o.tolerance)
 
 		// fmt.Print("Residual graph:\n")
 		// residual.PrintSelfWithFlow(nil)
@@ -107,7 +145,7 @@ func (n WeigthedNetwork[T]) MinCostMaxFlow() (flow map[*graph.WeightedDirectedEd
 		// fmt.Printf("Used BellmanFordMoore to find the following distances:\n")
 		// util.PrintMap(distances)
 
-		path, err := residual.ShortestPathWithMinHopFromDistances(distances, residual.Source, residual.Sink)
+		path, err := residual.ShortestPathWithMinHopFromDistancesWithin(distances, residual.Source, residual.Sink, o.tolerance)
 		if err != nil {
 			break // no augmenting path found means we are done
 		}
@@ -117,6 +155,24 @@ func (n WeigthedNetwork[T]) MinCostMaxFlow() (flow map[*graph.WeightedDirectedEd
 
 		flow = residual.AugmentFlow(flow, *path)
 		// n.PrintSelfWithFlow(flow)
+
+		if o.observer != nil {
+			bottleneck := bottleneckOf(*path)
+			var pathCost N
+			for _, edge := range path.Edges {
+				pathCost += edge.Weight
+			}
+			value += bottleneck
+			cost += bottleneck * pathCost
+			o.observe(Augmentation[T, N]{
+				Iteration:  iter,
+				Path:       path.Edges,
+				Bottleneck: bottleneck,
+				PathCost:   pathCost,
+				FlowValue:  value,
+				FlowCost:   cost,
+			})
+		}
 	}
 
 	return
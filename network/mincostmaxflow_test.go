@@ -1,11 +1,17 @@
 package network_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/graph"
 	"github.com/JonasBernard/min-cost-max-flow/network"
+	"github.com/JonasBernard/min-cost-max-flow/trace"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
@@ -24,9 +30,9 @@ func TestTraversal(t *testing.T) {
 	b := graph.V(&TestNode{Name: "B"})
 	c := graph.V(&TestNode{Name: "C"})
 
-	g := graph.WeigthedDirectedGraph[TestNode]{
+	g := graph.WeigthedDirectedGraph[TestNode, float64]{
 		Vertices: []graph.Vertex[TestNode]{a, b, c},
-		Edges: []*graph.WeightedDirectedEdge[TestNode]{
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 			{VertexFrom: a, VertexTo: b, Weight: 2},
 		},
 	}
@@ -40,7 +46,7 @@ func TestTraversal(t *testing.T) {
 	util.PrintMap(depths)
 
 	print("Add an edge from B to C.\n")
-	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode]{VertexFrom: b, VertexTo: c, Weight: 3})
+	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode, float64]{VertexFrom: b, VertexTo: c, Weight: 3})
 
 	print("Executing DFS.\n")
 	parents, depths = g.DFS(a)
@@ -64,9 +70,9 @@ func TestIsPath(t *testing.T) {
 	b := graph.V(&TestNode{Name: "B"})
 	c := graph.V(&TestNode{Name: "C"})
 
-	g := graph.WeigthedDirectedGraph[TestNode]{
+	g := graph.WeigthedDirectedGraph[TestNode, float64]{
 		Vertices: []graph.Vertex[TestNode]{a, b, c},
-		Edges: []*graph.WeightedDirectedEdge[TestNode]{
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 			{VertexFrom: a, VertexTo: b, Weight: 2},
 		},
 	}
@@ -76,7 +82,7 @@ func TestIsPath(t *testing.T) {
 	fmt.Printf("Is graph path from b? %v\n", g.IsPathFrom(b)) // false
 	fmt.Printf("Is graph path from c? %v\n", g.IsPathFrom(c)) // false
 
-	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode]{VertexFrom: b, VertexTo: c, Weight: 3})
+	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode, float64]{VertexFrom: b, VertexTo: c, Weight: 3})
 
 	// is a path
 	fmt.Printf("Is graph path from a? %v\n", g.IsPathFrom(a)) // true
@@ -86,7 +92,7 @@ func TestIsPath(t *testing.T) {
 	d := graph.V(&TestNode{Name: "D"})
 
 	g.Vertices = append(g.Vertices, d)
-	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode]{VertexFrom: b, VertexTo: c, Weight: 3})
+	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode, float64]{VertexFrom: b, VertexTo: c, Weight: 3})
 
 	// has branches
 	fmt.Printf("Is graph path from a? %v\n", g.IsPathFrom(a)) // false
@@ -102,9 +108,9 @@ func TestShortestPathHop(t *testing.T) {
 	e := graph.V(&TestNode{Name: "E"})
 	f := graph.V(&TestNode{Name: "F"})
 
-	g := graph.WeigthedDirectedGraph[TestNode]{
+	g := graph.WeigthedDirectedGraph[TestNode, float64]{
 		Vertices: []graph.Vertex[TestNode]{a, b, c, d, e, f},
-		Edges: []*graph.WeightedDirectedEdge[TestNode]{
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 			{VertexFrom: a, VertexTo: b, Weight: 2},
 			{VertexFrom: a, VertexTo: c, Weight: 2},
 			{VertexFrom: d, VertexTo: f, Weight: 2},
@@ -120,7 +126,7 @@ func TestShortestPathHop(t *testing.T) {
 	}
 
 	print("Adding edge from C to E.\n")
-	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode]{
+	g.Edges = append(g.Edges, &graph.WeightedDirectedEdge[TestNode, float64]{
 		VertexFrom: c, VertexTo: e, Weight: 0,
 	})
 
@@ -141,9 +147,9 @@ func TestShortestPathWeights(t *testing.T) {
 	f := graph.V(&TestNode{Name: "F"})
 
 	// https://upload.wikimedia.org/wikipedia/commons/thumb/3/3b/Shortest_path_with_direct_weights.svg/1200px-Shortest_path_with_direct_weights.svg.png
-	graph := graph.WeigthedDirectedGraph[TestNode]{
+	graph := graph.WeigthedDirectedGraph[TestNode, float64]{
 		Vertices: []graph.Vertex[TestNode]{a, b, c, d, e, f},
-		Edges: []*graph.WeightedDirectedEdge[TestNode]{
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 			// {VertexFrom: a, VertexTo: b, Weight: 4},
 			{VertexFrom: a, VertexTo: c, Weight: 2},
 			{VertexFrom: c, VertexTo: e, Weight: 3},
@@ -169,9 +175,9 @@ func TestNegativeCycle(t *testing.T) {
 	c := graph.V(&TestNode{Name: "C"})
 	d := graph.V(&TestNode{Name: "D"})
 
-	graph := graph.WeigthedDirectedGraph[TestNode]{
+	graph := graph.WeigthedDirectedGraph[TestNode, float64]{
 		Vertices: []graph.Vertex[TestNode]{a, b, c, d},
-		Edges: []*graph.WeightedDirectedEdge[TestNode]{
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 			{VertexFrom: a, VertexTo: b, Weight: 2},
 			{VertexFrom: b, VertexTo: c, Weight: -1},
 			{VertexFrom: c, VertexTo: b, Weight: -10},
@@ -198,25 +204,25 @@ func TestResidualGraph(test *testing.T) {
 	s := graph.V(&TestNode{Name: "S"})
 	t := graph.V(&TestNode{Name: "T"})
 
-	e1 := graph.E(s, a, 1, 5)
-	e2 := graph.E(s, b, 1, 5)
-	e3 := graph.E(a, c, 1, 3)
-	e4 := graph.E(a, d, 1, 2)
-	e5 := graph.E(b, c, 1, 1)
-	e6 := graph.E(b, d, 1, 7)
-	e7 := graph.E(c, t, 1, 2)
-	e8 := graph.E(d, t, 1, 2)
-
-	network := network.WeigthedNetwork[TestNode]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode]{
+	e1 := graph.E[TestNode, float64](s, a, 1, 5)
+	e2 := graph.E[TestNode, float64](s, b, 1, 5)
+	e3 := graph.E[TestNode, float64](a, c, 1, 3)
+	e4 := graph.E[TestNode, float64](a, d, 1, 2)
+	e5 := graph.E[TestNode, float64](b, c, 1, 1)
+	e6 := graph.E[TestNode, float64](b, d, 1, 7)
+	e7 := graph.E[TestNode, float64](c, t, 1, 2)
+	e8 := graph.E[TestNode, float64](d, t, 1, 2)
+
+	network := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
 			Vertices: []graph.Vertex[TestNode]{a, b, c, d, s, t},
-			Edges:    []*graph.WeightedDirectedEdge[TestNode]{e1, e2, e3, e4, e5, e6, e7, e8},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, float64]{e1, e2, e3, e4, e5, e6, e7, e8},
 		},
 		Source: s,
 		Sink:   t,
 	}
 
-	flow := make(map[*graph.WeightedDirectedEdge[TestNode]]float64)
+	flow := make(map[*graph.WeightedDirectedEdge[TestNode, float64]]float64)
 	flow[e1] = 3
 	flow[e3] = 1
 	flow[e4] = 2
@@ -239,18 +245,18 @@ func TestMinCostFlow(test *testing.T) {
 	s := graph.V(&TestNode{Name: "S"})
 	t := graph.V(&TestNode{Name: "T"})
 
-	network := network.WeigthedNetwork[TestNode]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode]{
+	network := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
 			Vertices: []graph.Vertex[TestNode]{a, b, s, t},
-			Edges: []*graph.WeightedDirectedEdge[TestNode]{
+			Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 				// short notation for edges
-				graph.E(s, a, 1, 5),
-				graph.E(s, b, 1, 3),
+				graph.E[TestNode, float64](s, a, 1, 5),
+				graph.E[TestNode, float64](s, b, 1, 3),
 
-				graph.E(b, a, 1, 1),
+				graph.E[TestNode, float64](b, a, 1, 1),
 
-				graph.E(a, t, 1, 4),
-				graph.E(b, t, 1, 4),
+				graph.E[TestNode, float64](a, t, 1, 4),
+				graph.E[TestNode, float64](b, t, 1, 4),
 			},
 		},
 		Source: s,
@@ -270,18 +276,18 @@ func TestMinCostFlow2(test *testing.T) {
 	s := graph.V(&TestNode{Name: "1"})
 	t := graph.V(&TestNode{Name: "4"})
 
-	network := network.WeigthedNetwork[TestNode]{
-		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode]{
+	network := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
 			Vertices: []graph.Vertex[TestNode]{a, b, s, t},
-			Edges: []*graph.WeightedDirectedEdge[TestNode]{
+			Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
 				// short notation for edges
-				graph.E(s, a, 1, 1),
-				graph.E(s, b, 5, 3),
+				graph.E[TestNode, float64](s, a, 1, 1),
+				graph.E[TestNode, float64](s, b, 5, 3),
 
-				graph.E(a, b, 1, 2),
+				graph.E[TestNode, float64](a, b, 1, 2),
 
-				graph.E(a, t, 4, 1),
-				graph.E(b, t, 2, 3),
+				graph.E[TestNode, float64](a, t, 4, 1),
+				graph.E[TestNode, float64](b, t, 2, 3),
 			},
 		},
 		Source: s,
@@ -293,3 +299,180 @@ func TestMinCostFlow2(test *testing.T) {
 	print("Computed maximal flow:\n")
 	util.PrintMap(flow)
 }
+
+func TestMinCostFlowContextDeadline(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	e1 := graph.E[TestNode, float64](s, a, 1, 5)
+	e2 := graph.E[TestNode, float64](a, t, 1, 4)
+
+	network := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{a, s, t},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, float64]{e1, e2},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	flow, err := network.MinCostMaxFlowContext(ctx)
+	if !errors.Is(err, util.ErrInterrupted) || !errors.Is(err, context.DeadlineExceeded) {
+		test.Fatalf("expected an interruption by the deadline, got %v", err)
+	}
+	if flow[e1] != 0 || flow[e2] != 0 {
+		test.Errorf("expected the zero flow as incumbent, got %v and %v", flow[e1], flow[e2])
+	}
+
+	flow, err = network.MinCostMaxFlowContext(context.Background())
+	if err != nil {
+		test.Fatalf("unexpected error: %v", err)
+	}
+	if flow[e1] != 4 || flow[e2] != 4 {
+		test.Errorf("expected a flow of 4 on both edges, got %v and %v", flow[e1], flow[e2])
+	}
+}
+
+func TestMinCostFlowSlogObserver(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	net := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{a, s, t},
+			Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
+				graph.E[TestNode, float64](s, a, 1, 5),
+				graph.E[TestNode, float64](a, t, 2, 4),
+				graph.E[TestNode, float64](s, t, 7, 1),
+			},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	net.MinCostMaxFlow(network.WithObserver(trace.NewSlogObserver(logger, slog.LevelInfo)))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		test.Fatalf("expected two augmentations to be logged, got:\n%v", out.String())
+	}
+	if !strings.Contains(lines[0], "bottleneck=4") || !strings.Contains(lines[0], "flowCost=12") {
+		test.Errorf("unexpected first augmentation: %v", lines[0])
+	}
+	if !strings.Contains(lines[1], "flowValue=5") || !strings.Contains(lines[1], "flowCost=19") {
+		test.Errorf("unexpected second augmentation: %v", lines[1])
+	}
+}
+
+func TestShortestPathWithDecimalDistances(test *testing.T) {
+	s := graph.V(&TestNode{Name: "S"})
+	a := graph.V(&TestNode{Name: "A"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	g := graph.WeigthedDirectedGraph[TestNode, float64]{
+		Vertices: []graph.Vertex[TestNode]{s, a, t},
+		Edges: []*graph.WeightedDirectedEdge[TestNode, float64]{
+			graph.E[TestNode, float64](s, a, 0.1, 1),
+			graph.E[TestNode, float64](a, t, 0.2, 1),
+		},
+	}
+
+	// distances as somebody would write them down, 0.1+0.2 is not exactly 0.3 in float64
+	distances := map[graph.Vertex[TestNode]]float64{s: 0, a: 0.1, t: 0.3}
+
+	if _, err := g.ShortestPathFromDistancesWithin(distances, s, t, util.Tolerance{}); err == nil {
+		test.Errorf("expected exact comparisons to miss the path")
+	}
+
+	path, err := g.ShortestPathFromDistances(distances, s, t)
+	if err != nil {
+		test.Fatalf("expected a path, got %v", err)
+	}
+	if len(path.Edges) != 2 {
+		test.Errorf("expected a path of two edges, got %v", path.Edges)
+	}
+}
+
+func TestMinCostFlowDecimal(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	b := graph.V(&TestNode{Name: "B"})
+	c := graph.V(&TestNode{Name: "C"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	sa := graph.E[TestNode, float64](s, a, 0.1, 0.1)
+	sb := graph.E[TestNode, float64](s, b, 0.2, 0.2)
+	ac := graph.E[TestNode, float64](a, c, 0.7, 0.7)
+	bc := graph.E[TestNode, float64](b, c, 0.3, 0.7)
+	ab := graph.E[TestNode, float64](a, b, 0.1, 0.3)
+	ct := graph.E[TestNode, float64](c, t, 0.3, 0.3)
+
+	net := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, c, s, t},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, float64]{sa, sb, ac, bc, ab, ct},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	flow := net.MinCostMaxFlow()
+
+	expected := map[*graph.WeightedDirectedEdge[TestNode, float64]]float64{sa: 0.1, sb: 0.2, ac: 0, bc: 0.3, ab: 0.1, ct: 0.3}
+	for e, f := range expected {
+		if !util.DefaultTolerance.Equal(flow[e], f) {
+			test.Errorf("expected flow %v on %v, got %v", f, e, flow[e])
+		}
+	}
+}
+
+func TestMinCostFlowInt64(test *testing.T) {
+	a := graph.V(&TestNode{Name: "2"})
+	b := graph.V(&TestNode{Name: "3"})
+
+	s := graph.V(&TestNode{Name: "1"})
+	t := graph.V(&TestNode{Name: "4"})
+
+	// 2^53 + 1 has no float64 representation
+	var large int64 = 1<<53 + 1
+
+	sa := graph.E[TestNode, int64](s, a, 1, 1)
+	sb := graph.E[TestNode, int64](s, b, 5, 3)
+	ab := graph.E[TestNode, int64](a, b, 1, 2)
+	at := graph.E[TestNode, int64](a, t, 4, 1)
+	bt := graph.E[TestNode, int64](b, t, 2, 3)
+	st := graph.E[TestNode, int64](s, t, 7, large)
+
+	net := network.WeigthedNetwork[TestNode, int64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, int64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, s, t},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, int64]{sa, sb, ab, at, bt, st},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	flow := net.MinCostMaxFlow()
+
+	expected := map[*graph.WeightedDirectedEdge[TestNode, int64]]int64{sa: 1, sb: 3, ab: 0, at: 1, bt: 3, st: large}
+	for e, f := range expected {
+		if flow[e] != f {
+			test.Errorf("expected flow %d on %v, got %d", f, e, flow[e])
+		}
+	}
+
+	var cost int64
+	for e, f := range flow {
+		cost += e.Weight * f
+	}
+	if cost != 26+7*large {
+		test.Errorf("expected cost %d, got %d", 26+7*large, cost)
+	}
+}
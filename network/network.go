@@ -2,10 +2,11 @@ package network
 
 import (
 	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
-type WeigthedNetwork[T graph.Node] struct {
-	graph.WeigthedDirectedGraph[T]
+type WeigthedNetwork[T graph.Node, N util.Number] struct {
+	graph.WeigthedDirectedGraph[T, N]
 	Source graph.Vertex[T]
 	Sink   graph.Vertex[T]
 }
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"github.com/JonasBernard/min-cost-max-flow/trace"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+// Configures the algorithms of this package. The zero configuration is used when no options are given.
+type Option func(*options)
+
+type options struct {
+	observer  trace.Observer
+	tolerance util.Tolerance
+}
+
+func collectOptions(opts []Option) (o options) {
+	o.tolerance = util.DefaultTolerance
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// Reports every augmentation to the given observer, see trace.SlogObserver.
+func WithObserver(observer trace.Observer) Option {
+	return func(o *options) {
+		o.observer = observer
+	}
+}
+
+/*
+Sets the tolerance used to compare path lengths and to decide whether an edge is saturated or
+carries flow. Defaults to util.DefaultTolerance.
+*/
+func WithTolerance(tolerance util.Tolerance) Option {
+	return func(o *options) {
+		o.tolerance = tolerance
+	}
+}
+
+// Returns the tolerance the given options configure, so that code building on this package can compare flows the same way.
+func ToleranceOf(opts ...Option) util.Tolerance {
+	return collectOptions(opts).tolerance
+}
+
+func (o options) observe(e trace.Event) {
+	if o.observer != nil {
+		o.observer.Observe(e)
+	}
+}
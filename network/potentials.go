@@ -0,0 +1,111 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrNotOptimal = errors.New("flow is not of minimal cost")
+
+/*
+Same as MinCostMaxFlow, but also returns node potentials that prove the flow to be of minimal cost
+among all flows of its value, see Potentials and VerifyOptimality.
+*/
+func (n WeigthedNetwork[T, N]) MinCostMaxFlowWithPotentials(opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N) {
+	flow, potentials, _ = n.MinCostMaxFlowWithPotentialsContext(context.Background(), opts...)
+	return
+}
+
+/*
+Same as MinCostMaxFlowWithPotentials, but stops once ctx is done like MinCostMaxFlowContext. As the
+flow built up so far has minimal cost for its value, potentials are returned in that case as well.
+*/
+func (n WeigthedNetwork[T, N]) MinCostMaxFlowWithPotentialsContext(ctx context.Context, opts ...Option) (flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, err error) {
+	flow, err = n.MinCostMaxFlowContext(ctx, opts...)
+	potentials, potentialsErr := n.Potentials(flow, opts...)
+	if potentialsErr != nil {
+		return flow, nil, potentialsErr
+	}
+	return flow, potentials, err
+}
+
+/*
+Computes node potentials for the given flow such that the reduced cost
+weight(e) + potential(from) - potential(to) of every arc e of the residual graph is non-negative,
+with potential zero at the source. They exist if and only if the residual graph has no negative
+cycle, that is if the flow has minimal cost among all flows of its value, and ErrNotOptimal is
+returned otherwise.
+
+The potentials are the shortest distances in the residual graph from an extra vertex with arcs of
+weight zero to all vertices, so they are finite even for vertices that the source cannot reach.
+*/
+func (n WeigthedNetwork[T, N]) Potentials(flow map[*graph.WeightedDirectedEdge[T, N]]N, opts ...Option) (potentials map[graph.Vertex[T]]N, err error) {
+	o := collectOptions(opts)
+	residual := n.ResidualGraphWithin(flow, o.tolerance)
+
+	root := graph.V(new(T))
+	g := graph.WeigthedDirectedGraph[T, N]{
+		Vertices: append([]graph.Vertex[T]{root}, n.Vertices...),
+		Edges:    residual.Edges,
+	}
+	for _, v := range n.Vertices {
+		g.Edges = append(g.Edges, graph.E[T, N](root, v, 0, 0))
+	}
+	distances := g.BellmanFordMooreWithin(root, o.tolerance)
+
+	for _, e := range residual.Edges {
+		// the reduced cost is tested like in VerifyOptimality, as the relative tolerance of the distances would hide it
+		if reduced := e.Weight + distances[e.VertexFrom] - distances[e.VertexTo]; util.SignWithin(o.tolerance, reduced) < 0 {
+			return nil, fmt.Errorf("%w: the residual graph has a negative cycle through %v", ErrNotOptimal, e)
+		}
+	}
+
+	source := distances[n.Source]
+	potentials = make(map[graph.Vertex[T]]N, len(n.Vertices))
+	for _, v := range n.Vertices {
+		potentials[v] = distances[v] - source
+	}
+	return potentials, nil
+}
+
+/*
+Checks complementary slackness of the flow and the potentials: every edge with positive reduced
+cost weight(e) + potential(from) - potential(to) must be empty, every edge with negative reduced
+cost must be saturated and every edge with flow strictly between zero and its capacity must have
+reduced cost zero. Together with feasibility of the flow, this proves that it has minimal cost
+among all flows of its value. Returns an error matching ErrNotOptimal that lists every violation,
+including flows outside of [0, capacity] and edges whose vertices have no potential.
+*/
+func (n WeigthedNetwork[T, N]) VerifyOptimality(flow map[*graph.WeightedDirectedEdge[T, N]]N, potentials map[graph.Vertex[T]]N, opts ...Option) error {
+	tol := collectOptions(opts).tolerance
+	var violations []error
+	violation := func(format string, args ...any) {
+		violations = append(violations, fmt.Errorf("%w: %v", ErrNotOptimal, fmt.Sprintf(format, args...)))
+	}
+
+	for _, e := range n.Edges {
+		f := flow[e]
+		if util.SignWithin(tol, f) < 0 || util.CompareWithin(tol, f, e.Capacity) > 0 {
+			violation("flow %v on %v is outside of [0, %v]", f, e, e.Capacity)
+			continue
+		}
+		from, okFrom := potentials[e.VertexFrom]
+		to, okTo := potentials[e.VertexTo]
+		if !okFrom || !okTo {
+			violation("missing potential for an endpoint of %v", e)
+			continue
+		}
+		reduced := e.Weight + from - to
+		switch {
+		case util.SignWithin(tol, reduced) > 0 && util.SignWithin(tol, f) > 0:
+			violation("%v has reduced cost %v, but carries flow %v", e, reduced, f)
+		case util.SignWithin(tol, reduced) < 0 && util.CompareWithin(tol, f, e.Capacity) < 0:
+			violation("%v has reduced cost %v, but flow %v below its capacity %v", e, reduced, f, e.Capacity)
+		}
+	}
+	return errors.Join(violations...)
+}
@@ -0,0 +1,116 @@
+package network_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/network"
+)
+
+func TestPotentials(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	b := graph.V(&TestNode{Name: "B"})
+	c := graph.V(&TestNode{Name: "C"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+	// not reachable from the source at all
+	u := graph.V(&TestNode{Name: "U"})
+
+	cheap := graph.E[TestNode, int64](s, a, 1, 2)
+	expensive := graph.E[TestNode, int64](s, b, 5, 2)
+	net := network.WeigthedNetwork[TestNode, int64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, int64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, c, s, t, u},
+			Edges: []*graph.WeightedDirectedEdge[TestNode, int64]{
+				cheap,
+				expensive,
+				graph.E[TestNode, int64](a, c, 1, 3),
+				graph.E[TestNode, int64](b, c, 1, 3),
+				graph.E[TestNode, int64](c, t, 0, 2),
+				graph.E[TestNode, int64](u, t, -4, 1),
+			},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	flow, potentials := net.MinCostMaxFlowWithPotentials()
+	if potentials[s] != 0 {
+		test.Errorf("expected potential zero at the source, got %v", potentials[s])
+	}
+	if err := net.VerifyOptimality(flow, potentials); err != nil {
+		test.Errorf("expected the flow to be optimal, got %v", err)
+	}
+	checkPotentials(test, net, flow, potentials)
+
+	// both units along the expensive path instead of the cheap one
+	worse := map[*graph.WeightedDirectedEdge[TestNode, int64]]int64{}
+	for _, e := range net.Edges {
+		if e.VertexFrom.Node != a.Node && e.VertexFrom.Node != u.Node && e != cheap {
+			worse[e] = 2
+		}
+	}
+	if _, err := net.Potentials(worse); !errors.Is(err, network.ErrNotOptimal) {
+		test.Errorf("expected a negative cycle, got %v", err)
+	}
+	err := net.VerifyOptimality(worse, potentials)
+	if !errors.Is(err, network.ErrNotOptimal) {
+		test.Fatalf("expected the flow not to be optimal, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "[B] [C] (wgt 1, cap 3) has reduced cost 4, but carries flow 2") {
+		test.Errorf("expected the edge from B to C to be too expensive, got %v", err)
+	}
+
+	delete(potentials, u)
+	if err := net.VerifyOptimality(flow, potentials); !errors.Is(err, network.ErrNotOptimal) {
+		test.Errorf("expected a missing potential, got %v", err)
+	}
+}
+
+func TestPotentialsWithLargeWeights(test *testing.T) {
+	x := graph.V(&TestNode{Name: "X"})
+	y := graph.V(&TestNode{Name: "Y"})
+	cheap := graph.E[TestNode, float64](x, y, 1e12, 1)
+	expensive := graph.E[TestNode, float64](x, y, 1e12+100, 1)
+	net := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{x, y},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, float64]{cheap, expensive},
+		},
+		Source: x,
+		Sink:   y,
+	}
+
+	// the cycle along the cheap edge and back along the expensive one has cost -100, which is
+	// far below the relative tolerance of the weights
+	flow := map[*graph.WeightedDirectedEdge[TestNode, float64]]float64{expensive: 1}
+	if _, err := net.Potentials(flow); !errors.Is(err, network.ErrNotOptimal) {
+		test.Errorf("expected a negative cycle, got %v", err)
+	}
+}
+
+func TestPotentialsOfRandomNetworks(test *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for k := range 100 {
+		net := randomNetwork(r, 2+r.Intn(7))
+		flow, potentials := net.MinCostMaxFlowWithPotentials()
+		if err := net.VerifyOptimality(flow, potentials); err != nil {
+			test.Errorf("network %v: %v", k, err)
+		}
+
+		lpFlow, lpPotentials, err := net.MinCostMaxFlowLP()
+		if err != nil {
+			test.Fatalf("network %v: unexpected error: %v", k, err)
+		}
+		if err := net.VerifyOptimality(lpFlow, lpPotentials); err != nil {
+			test.Errorf("network %v: potentials of the LP: %v", k, err)
+		}
+		// potentials of one optimal flow prove the optimality of all others of the same value
+		if err := net.VerifyOptimality(flow, lpPotentials); err != nil {
+			test.Errorf("network %v: potentials of the LP for MinCostMaxFlow: %v", k, err)
+		}
+	}
+}
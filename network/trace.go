@@ -0,0 +1,38 @@
+package network
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+Reported by MinCostMaxFlow after every augmentation. Path consists of the arcs of the residual
+graph that were used, Bottleneck is the amount of flow that was sent along it and PathCost the
+cost of sending one unit. FlowValue and FlowCost describe the whole flow after the augmentation.
+*/
+type Augmentation[T graph.Node, N util.Number] struct {
+	Iteration  int
+	Path       []*graph.WeightedDirectedEdge[T, N]
+	Bottleneck N
+	PathCost   N
+	FlowValue  N
+	FlowCost   N
+}
+
+func (e Augmentation[T, N]) Kind() string {
+	return "flow augmentation"
+}
+
+func (e Augmentation[T, N]) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.String("path", fmt.Sprint(e.Path)),
+		slog.Any("bottleneck", e.Bottleneck),
+		slog.Any("pathCost", e.PathCost),
+		slog.Any("flowValue", e.FlowValue),
+		slog.Any("flowCost", e.FlowCost),
+	}
+}
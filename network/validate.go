@@ -0,0 +1,144 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+var ErrInvalidFlow = errors.New("invalid flow")
+
+type ViolationKind int
+
+const (
+	// the flow on an edge is negative
+	NegativeFlow ViolationKind = iota
+	// the flow on an edge exceeds its capacity
+	CapacityExceeded
+	// inflow and outflow differ at a vertex other than source and sink
+	ConservationViolated
+	// the net outflow of the source differs from the net inflow of the sink, which conservation
+	// implies unless edges lead to vertices that are not part of the network
+	ValueMismatch
+	// the flow map has an edge that is not part of the network
+	UnknownEdge
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case NegativeFlow:
+		return "negative flow"
+	case CapacityExceeded:
+		return "capacity exceeded"
+	case ConservationViolated:
+		return "conservation violated"
+	case ValueMismatch:
+		return "value mismatch"
+	case UnknownEdge:
+		return "unknown edge"
+	}
+	return fmt.Sprintf("ViolationKind(%d)", int(k))
+}
+
+/*
+A violation found by ValidateFlow. Edge is set for NegativeFlow, CapacityExceeded and UnknownEdge,
+Vertex for ConservationViolated. Amount is the offending value: the flow on the edge, the inflow
+minus the outflow at the vertex or the net outflow of the source. Limit is what it should be: the
+capacity, zero or the net inflow of the sink.
+*/
+type Violation[T graph.Node, N util.Number] struct {
+	Kind   ViolationKind
+	Edge   *graph.WeightedDirectedEdge[T, N]
+	Vertex graph.Vertex[T]
+	Amount N
+	Limit  N
+}
+
+func (v Violation[T, N]) String() string {
+	switch v.Kind {
+	case NegativeFlow:
+		return fmt.Sprintf("%v: flow %v on %v", v.Kind, v.Amount, v.Edge)
+	case CapacityExceeded:
+		return fmt.Sprintf("%v: flow %v on %v above capacity %v", v.Kind, v.Amount, v.Edge, v.Limit)
+	case ConservationViolated:
+		return fmt.Sprintf("%v: inflow exceeds outflow by %v at %v", v.Kind, v.Amount, v.Vertex)
+	case ValueMismatch:
+		return fmt.Sprintf("%v: source sends %v, sink receives %v", v.Kind, v.Amount, v.Limit)
+	case UnknownEdge:
+		return fmt.Sprintf("%v: flow %v on %v", v.Kind, v.Amount, v.Edge)
+	}
+	return v.Kind.String()
+}
+
+/*
+The result of ValidateFlow: the value of the flow (the net outflow of the source), its total cost
+and every violation found, which is empty for a feasible flow.
+*/
+type FlowReport[T graph.Node, N util.Number] struct {
+	Value      N
+	Cost       N
+	Violations []Violation[T, N]
+}
+
+func (r FlowReport[T, N]) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Nil for a feasible flow, otherwise an error matching ErrInvalidFlow that lists all violations.
+func (r FlowReport[T, N]) Err() error {
+	var errs []error
+	for _, v := range r.Violations {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidFlow, v))
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Checks a flow against the network: the flow on every edge must lie within zero and its capacity,
+inflow and outflow must be equal at every vertex other than source and sink, and the net outflow
+of the source must equal the net inflow of the sink. Edges without an entry in the flow map carry
+no flow, entries for edges that are not part of the network are reported as UnknownEdge.
+Comparisons use the tolerance of the options, and are exact for int64.
+*/
+func (n WeigthedNetwork[T, N]) ValidateFlow(flow map[*graph.WeightedDirectedEdge[T, N]]N, opts ...Option) (report FlowReport[T, N]) {
+	tol := collectOptions(opts).tolerance
+
+	known := make(map[*graph.WeightedDirectedEdge[T, N]]bool, len(n.Edges))
+	// inflow minus outflow of every vertex
+	excess := make(map[*T]N, len(n.Vertices))
+	for _, e := range n.Edges {
+		known[e] = true
+		f := flow[e]
+		if util.SignWithin(tol, f) < 0 {
+			report.Violations = append(report.Violations, Violation[T, N]{Kind: NegativeFlow, Edge: e, Amount: f})
+		}
+		if util.CompareWithin(tol, f, e.Capacity) > 0 {
+			report.Violations = append(report.Violations, Violation[T, N]{Kind: CapacityExceeded, Edge: e, Amount: f, Limit: e.Capacity})
+		}
+		excess[e.VertexFrom.Node] -= f
+		excess[e.VertexTo.Node] += f
+		report.Cost += f * e.Weight
+	}
+	for e, f := range flow {
+		if !known[e] && util.SignWithin(tol, f) != 0 {
+			report.Violations = append(report.Violations, Violation[T, N]{Kind: UnknownEdge, Edge: e, Amount: f})
+		}
+	}
+
+	for _, v := range n.Vertices {
+		if v.Node == n.Source.Node || v.Node == n.Sink.Node {
+			continue
+		}
+		if util.SignWithin(tol, excess[v.Node]) != 0 {
+			report.Violations = append(report.Violations, Violation[T, N]{Kind: ConservationViolated, Vertex: v, Amount: excess[v.Node]})
+		}
+	}
+
+	report.Value = -excess[n.Source.Node]
+	if received := excess[n.Sink.Node]; !util.EqualWithin(tol, report.Value, received) {
+		report.Violations = append(report.Violations, Violation[T, N]{Kind: ValueMismatch, Amount: report.Value, Limit: received})
+	}
+	return report
+}
@@ -0,0 +1,107 @@
+package network_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/graph"
+	"github.com/JonasBernard/min-cost-max-flow/network"
+)
+
+func TestValidateFlow(test *testing.T) {
+	a := graph.V(&TestNode{Name: "A"})
+	b := graph.V(&TestNode{Name: "B"})
+	s := graph.V(&TestNode{Name: "S"})
+	t := graph.V(&TestNode{Name: "T"})
+
+	sa := graph.E[TestNode, float64](s, a, 1, 5)
+	sb := graph.E[TestNode, float64](s, b, 1, 3)
+	ba := graph.E[TestNode, float64](b, a, 1, 1)
+	at := graph.E[TestNode, float64](a, t, 1, 4)
+	bt := graph.E[TestNode, float64](b, t, 1, 4)
+	net := network.WeigthedNetwork[TestNode, float64]{
+		WeigthedDirectedGraph: graph.WeigthedDirectedGraph[TestNode, float64]{
+			Vertices: []graph.Vertex[TestNode]{a, b, s, t},
+			Edges:    []*graph.WeightedDirectedEdge[TestNode, float64]{sa, sb, ba, at, bt},
+		},
+		Source: s,
+		Sink:   t,
+	}
+
+	report := net.ValidateFlow(net.MinCostMaxFlow())
+	if !report.Valid() || report.Err() != nil {
+		test.Errorf("expected a valid flow, got %v", report.Violations)
+	}
+	if report.Value != 7 || report.Cost != 14 {
+		test.Errorf("expected value 7 at cost 14, got %v at cost %v", report.Value, report.Cost)
+	}
+
+	elsewhere := graph.E[TestNode, float64](a, b, 1, 1)
+	report = net.ValidateFlow(map[*graph.WeightedDirectedEdge[TestNode, float64]]float64{
+		sa: 6, sb: -1, at: 4, bt: 1, elsewhere: 2,
+	})
+	kinds := map[network.ViolationKind]int{}
+	for _, v := range report.Violations {
+		kinds[v.Kind]++
+	}
+	expected := map[network.ViolationKind]int{
+		network.NegativeFlow:         1,
+		network.CapacityExceeded:     1,
+		network.ConservationViolated: 2,
+		network.UnknownEdge:          1,
+	}
+	if len(kinds) != len(expected) {
+		test.Errorf("expected violations %v, got %v", expected, report.Violations)
+	}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			test.Errorf("expected %v violations of kind %v, got %v", count, kind, report.Violations)
+		}
+	}
+	if report.Value != 5 {
+		test.Errorf("expected value 5, got %v", report.Value)
+	}
+
+	err := report.Err()
+	if !errors.Is(err, network.ErrInvalidFlow) {
+		test.Fatalf("expected an invalid flow, got %v", err)
+	}
+	for _, message := range []string{
+		"capacity exceeded: flow 6 on [S] [A] (wgt 1, cap 5) above capacity 5",
+		"negative flow: flow -1 on [S] [B] (wgt 1, cap 3)",
+		"conservation violated: inflow exceeds outflow by 2 at [A]",
+		"unknown edge: flow 2 on [A] [B] (wgt 1, cap 1)",
+	} {
+		if !strings.Contains(err.Error(), message) {
+			test.Errorf("expected %q in %v", message, err)
+		}
+	}
+
+	// flow that leaves the network through a vertex that is not part of it
+	x := graph.V(&TestNode{Name: "X"})
+	ax := graph.E[TestNode, float64](a, x, 1, 1)
+	net.Edges = append(net.Edges, ax)
+	report = net.ValidateFlow(map[*graph.WeightedDirectedEdge[TestNode, float64]]float64{sa: 1, ax: 1})
+	if len(report.Violations) != 1 || report.Violations[0].Kind != network.ValueMismatch {
+		test.Errorf("expected a value mismatch, got %v", report.Violations)
+	} else if message := report.Violations[0].String(); message != "value mismatch: source sends 1, sink receives 0" {
+		test.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestValidateFlowOfRandomNetworks(test *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for k := range 100 {
+		net := randomNetwork(r, 2+r.Intn(7))
+		flow := net.MinCostMaxFlow()
+		report := net.ValidateFlow(flow)
+		if !report.Valid() {
+			test.Errorf("network %v: %v", k, report.Err())
+		}
+		if value, cost := valueAndCost(net, flow); report.Value != value || report.Cost != cost {
+			test.Errorf("network %v: expected value %v at cost %v, got %v at cost %v", k, value, cost, report.Value, report.Cost)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package numbers
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+)
+
+/*
+Arithmetic of an ordered field with elements of type T.
+
+The linear algebra and the simplex implementations in package lp are written against this
+interface, so the same code runs in floating point arithmetic (Float64) and in exact rational
+arithmetic (Rational). Implementations must never modify their arguments.
+*/
+type Field[T any] interface {
+	Zero() T
+	One() T
+	// Converts a float64 to the field. Non-finite values are only supported by Float64.
+	FromFloat(float64) T
+	Float(T) float64
+	Add(a, b T) T
+	Sub(a, b T) T
+	Mul(a, b T) T
+	Div(a, b T) T
+	Neg(a T) T
+	Abs(a T) T
+	Cmp(a, b T) int
+	// Returns -1, 0 or +1 depending on whether a is negative, zero or positive.
+	Sign(a T) int
+	String(a T) string
+}
+
+/*
+The float64 field. Comparisons and signs respect the given tolerance, so that rounding errors
+like 0.1+0.2-0.3 count as zero. The zero value compares exactly.
+*/
+type Float64 struct {
+	Tolerance util.Tolerance
+}
+
+func (Float64) Zero() float64               { return 0 }
+func (Float64) One() float64                { return 1 }
+func (Float64) FromFloat(v float64) float64 { return v }
+func (Float64) Float(a float64) float64     { return a }
+func (Float64) Add(a, b float64) float64    { return a + b }
+func (Float64) Sub(a, b float64) float64    { return a - b }
+func (Float64) Mul(a, b float64) float64    { return a * b }
+func (Float64) Div(a, b float64) float64    { return a / b }
+func (Float64) Neg(a float64) float64       { return -a }
+func (Float64) Abs(a float64) float64       { return math.Abs(a) }
+func (Float64) String(a float64) string     { return strconv.FormatFloat(a, 'g', -1, 64) }
+func (f Float64) Cmp(a, b float64) int      { return f.Tolerance.Compare(a, b) }
+func (f Float64) Sign(a float64) int        { return f.Tolerance.Sign(a) }
+
+/*
+The field of rational numbers in exact arithmetic. Every operation allocates a new *big.Rat.
+
+FromFloat converts a float64 via its shortest decimal representation, so 0.1 becomes 1/10
+rather than the binary fraction closest to it.
+*/
+type Rational struct{}
+
+func (Rational) Zero() *big.Rat { return new(big.Rat) }
+func (Rational) One() *big.Rat  { return big.NewRat(1, 1) }
+
+func (Rational) FromFloat(v float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	if !ok {
+		panic("cannot represent " + strconv.FormatFloat(v, 'g', -1, 64) + " as rational number")
+	}
+	return r
+}
+
+func (Rational) Float(a *big.Rat) float64 {
+	v, _ := a.Float64()
+	return v
+}
+
+func (Rational) Add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+func (Rational) Sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+func (Rational) Mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+func (Rational) Div(a, b *big.Rat) *big.Rat { return new(big.Rat).Quo(a, b) }
+func (Rational) Neg(a *big.Rat) *big.Rat    { return new(big.Rat).Neg(a) }
+func (Rational) Abs(a *big.Rat) *big.Rat    { return new(big.Rat).Abs(a) }
+func (Rational) Cmp(a, b *big.Rat) int      { return a.Cmp(b) }
+func (Rational) Sign(a *big.Rat) int        { return a.Sign() }
+func (Rational) String(a *big.Rat) string   { return a.RatString() }
+
+// Converts every entry of v to the field f.
+func FromFloats[T any](f Field[T], v []float64) []T {
+	result := make([]T, len(v))
+	for i, x := range v {
+		result[i] = f.FromFloat(x)
+	}
+	return result
+}
+
+// Converts every entry of A to the field f.
+func FromFloatMatrix[T any](f Field[T], A [][]float64) [][]T {
+	result := make([][]T, len(A))
+	for i, row := range A {
+		result[i] = FromFloats(f, row)
+	}
+	return result
+}
+
+// Converts every entry of v from the field f to float64.
+func ToFloats[T any](f Field[T], v []T) []float64 {
+	if v == nil {
+		return nil
+	}
+	result := make([]float64, len(v))
+	for i, x := range v {
+		result[i] = f.Float(x)
+	}
+	return result
+}
+
+// Converts every entry of A from the field f to float64.
+func ToFloatMatrix[T any](f Field[T], A [][]T) [][]float64 {
+	if A == nil {
+		return nil
+	}
+	result := make([][]float64, len(A))
+	for i, row := range A {
+		result[i] = ToFloats(f, row)
+	}
+	return result
+}
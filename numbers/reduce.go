@@ -1,14 +1,94 @@
 package numbers
 
 import (
-	"fmt"
+	"io"
+	"log/slog"
 	"math"
-	"os"
 
+	"github.com/JonasBernard/min-cost-max-flow/trace"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
-func Reduce(rational float64) (p int, q int) {
+// Reported by Reduce for every step of the continued fraction expansion.
+type ReduceStep struct {
+	Iteration int
+	A         int
+	P         int
+	Q         int
+	R         float64
+	Diff      float64
+}
+
+func (e ReduceStep) Kind() string {
+	return "continued fraction step"
+}
+
+func (e ReduceStep) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("iteration", e.Iteration),
+		slog.Int("a", e.A),
+		slog.Int("p", e.P),
+		slog.Int("q", e.Q),
+		slog.Float64("r", e.R),
+		slog.Float64("diff", e.Diff),
+	}
+}
+
+// Reported by Reduce when it returns. Converged is false if it gave up after the maximum number of steps.
+type ReduceFinished struct {
+	P         int
+	Q         int
+	Converged bool
+}
+
+func (e ReduceFinished) Kind() string {
+	return "continued fraction finished"
+}
+
+func (e ReduceFinished) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Int("p", e.P),
+		slog.Int("q", e.Q),
+		slog.Bool("converged", e.Converged),
+	}
+}
+
+// Renders the steps of every call to Reduce as a table, like Reduce used to do on stdout.
+type TableObserver struct {
+	out   io.Writer
+	table table.Writer
+}
+
+func NewTableObserver(out io.Writer) *TableObserver {
+	return &TableObserver{out: out}
+}
+
+func (o *TableObserver) Observe(e trace.Event) {
+	if o.table == nil {
+		o.table = table.NewWriter()
+		o.table.SetOutputMirror(o.out)
+		o.table.AppendHeader(table.Row{"iter", "a_i", "p_i", "q_i", "r_i", "diff"})
+	}
+	switch e := e.(type) {
+	case ReduceStep:
+		o.table.AppendRow(table.Row{e.Iteration, e.A, e.P, e.Q, e.R, e.Diff})
+		o.table.AppendSeparator()
+	case ReduceFinished:
+		if !e.Converged {
+			o.table.AppendFooter(table.Row{"max iterations reached"})
+		}
+		o.table.Render()
+		o.table = nil
+	}
+}
+
+/*
+Computes a fraction p/q that approximates the given rational via its continued fraction expansion.
+An observer (for example a TableObserver) can be given to follow the expansion.
+*/
+func Reduce(rational float64, observer ...trace.Observer) (p int, q int) {
+	observe := trace.Observers(observer)
+
 	rs := make([]float64, 0)
 	ps := make([]int, 2)
 	qs := make([]int, 2)
@@ -20,11 +100,7 @@ func Reduce(rational float64) (p int, q int) {
 
 	rs = append(rs, rational)
 
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-
-	t.AppendHeader(table.Row{"iter", "a_i", "p_i", "q_i", "r_i", "diff"})
-
+	converged := true
 	i := 2
 	for {
 		a := int(math.Floor(rs[i-2]))
@@ -32,8 +108,7 @@ func Reduce(rational float64) (p int, q int) {
 		qs = append(qs, a*qs[i-1]+qs[i-2])
 		diff := rs[i-2] - float64(a)
 
-		t.AppendRow(table.Row{i - 2, a, ps[i], qs[i], rs[i-2], diff})
-		t.AppendSeparator()
+		observe.Observe(ReduceStep{Iteration: i - 2, A: a, P: ps[i], Q: qs[i], R: rs[i-2], Diff: diff})
 
 		if diff < 1e-10 {
 			break
@@ -41,13 +116,36 @@ func Reduce(rational float64) (p int, q int) {
 		rs = append(rs, 1/diff)
 
 		if i > 10 {
-			fmt.Printf("max iterations reached")
+			converged = false
 			break
 		}
 		i++
 	}
 
-	t.Render()
+	observe.Observe(ReduceFinished{P: ps[i], Q: qs[i], Converged: converged})
 
 	return ps[i], qs[i]
 }
+
+/*
+Computes the fraction p/q with q <= maxDenominator that is closest to the given value, provided
+the value is within 1/(2*maxDenominator^2) of such a fraction. By Legendre's theorem that fraction is
+a convergent of the continued fraction expansion of the value, and no later convergent with a
+denominator of at most maxDenominator can be as close, so it is the last one.
+*/
+func ReduceWithDenominator(value float64, maxDenominator int) (p int, q int) {
+	pPrev, qPrev := 1, 0
+	p, q = int(math.Floor(value)), 1
+	r := value - math.Floor(value)
+	for r > 1e-12 {
+		r = 1 / r
+		a := int(math.Floor(r))
+		if a*q+qPrev > maxDenominator {
+			break
+		}
+		p, pPrev = a*p+pPrev, p
+		q, qPrev = a*q+qPrev, q
+		r -= float64(a)
+	}
+	return p, q
+}
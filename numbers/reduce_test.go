@@ -1,6 +1,7 @@
 package numbers_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/numbers"
@@ -12,3 +13,25 @@ func TestReduce(t *testing.T) {
 	assert.Equal(t, 33, p)
 	assert.Equal(t, 100, q)
 }
+
+func TestReduceTable(t *testing.T) {
+	var out bytes.Buffer
+	p, q := numbers.Reduce(0.75, numbers.NewTableObserver(&out))
+	assert.Equal(t, 3, p)
+	assert.Equal(t, 4, q)
+	assert.Contains(t, out.String(), "P_I")
+}
+
+func TestReduceWithDenominator(t *testing.T) {
+	p, q := numbers.ReduceWithDenominator(13.0/5+1e-5, 50)
+	assert.Equal(t, 13, p)
+	assert.Equal(t, 5, q)
+
+	p, q = numbers.ReduceWithDenominator(-7.0/3-1e-6, 10)
+	assert.Equal(t, -7, p)
+	assert.Equal(t, 3, q)
+
+	p, q = numbers.ReduceWithDenominator(4, 10)
+	assert.Equal(t, 4, p)
+	assert.Equal(t, 1, q)
+}
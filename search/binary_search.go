@@ -1,36 +1,134 @@
 package search
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math"
+	"slices"
 
 	"github.com/JonasBernard/min-cost-max-flow/lp"
 	"github.com/JonasBernard/min-cost-max-flow/numbers"
 	"github.com/JonasBernard/min-cost-max-flow/util"
 )
 
-func BinarySearch(A [][]float64, b []float64, c []float64, K int) int {
-	n := len(A[0])
+var ErrTooLarge = errors.New("the linear program needs more precision than float64 has")
 
-	Q := math.Pow(float64(n*K), float64(4*n*n))
-	l := -Q
-	u := Q
+/*
+Maximizes c@x s.t. A@x <= b, x >= 0 for integral A, b and c with absolute values of at most K by a
+binary search over the optimal value, where the ellipsoid method decides whether c@x >= lambda can be met.
 
-	for u-l > 1/(4*Q*Q) {
+The optimal value is attained at a vertex, so it is a fraction p/q with q <= D = (sqrt(n)*K)^n and
+absolute value at most n*K*D, see lp.EllipsoidFeasibility. Two such fractions differ by at least
+1/D^2, so the search stops once its interval is shorter than 1/(4*D^2) and rounds to the closest
+fraction with a denominator of at most D. Before that, lp.EllipsoidFeasibility decides whether
+the program is feasible at all and whether it has an improving ray, returning lp.ErrInfeasible
+or lp.ErrUnbounded.
+
+Since D grows exponentially with n, this only works for small programs: if float64 cannot resolve
+the relaxation of isFeasible next to values of size n*K*D, ErrTooLarge is returned right away.
+*/
+func BinarySearch(A [][]float64, b []float64, c []float64, K int) (p int, q int, err error) {
+	return BinarySearchContext(context.Background(), A, b, c, K)
+}
+
+// Same as BinarySearch, but gives up once ctx is done, returning an error that matches util.ErrInterrupted.
+func BinarySearchContext(ctx context.Context, A [][]float64, b []float64, c []float64, K int) (p int, q int, err error) {
+	k := max(float64(K), maxEntry(A, b, c))
+	n := float64(len(c))
+	D := vertexBound(len(c), k)
+	if bound := n*k*D + 1; math.Nextafter(bound, math.Inf(1))-bound > relaxation(len(A), len(c), D) {
+		return 0, 0, fmt.Errorf("%w: %v variables with entries of up to %v allow denominators of up to %v", ErrTooLarge, len(c), k, D)
+	}
+
+	_, feasible, err := lp.EllipsoidFeasibilityContext(ctx, A, b, k)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !feasible {
+		return 0, 0, lp.ErrInfeasible
+	}
+	// an improving ray d >= 0 with A@d <= 0 and c@d >= 1
+	rays := slices.Concat(A, [][]float64{util.Neg(c)})
+	_, unbounded, err := lp.EllipsoidFeasibilityContext(ctx, rays, slices.Concat(make([]float64, len(A)), []float64{-1}), k)
+	if err != nil {
+		return 0, 0, err
+	}
+	if unbounded {
+		return 0, 0, lp.ErrUnbounded
+	}
+
+	l := -n*k*D - 1
+	u := n*k*D + 1
+	// c@x >= l can be met, c@x >= u cannot
+	for u-l >= 1/(4*D*D) {
 		mid := (l + u) / 2
-		if IsFeasible(A, b, c, mid) {
-			u = mid
-		} else {
+		if mid == l || mid == u {
+			// no float64 is left between l and u, which the check for ErrTooLarge should rule out
+			break
+		}
+		feasible, err := isFeasible(ctx, A, b, c, mid, k)
+		if err != nil {
+			return 0, 0, err
+		}
+		if feasible {
 			l = mid
+		} else {
+			u = mid
 		}
 	}
 
-	p, q := numbers.Reduce(u)
-	return p / q
+	p, q = numbers.ReduceWithDenominator((l+u)/2, int(math.Ceil(D)))
+	return p, q, nil
 }
 
+// Whether A@x <= b, x >= 0 has a solution with c@x >= lambda, decided by the ellipsoid method.
 func IsFeasible(A [][]float64, b []float64, c []float64, lambda float64) bool {
-	A = append(A, util.Neg(c))
-	b = append(b, -lambda)
-	_, feasible, _ := lp.PhaseOne(A, b, false)
+	feasible, _ := isFeasible(context.Background(), A, b, c, lambda, maxEntry(A, b, c))
 	return feasible
 }
+
+/*
+Relaxes every row of A@x <= b, x >= 0, c@x >= lambda by eta, so that a ball of radius eta/(sqrt(n)*K)
+around an optimal vertex satisfies it if lambda is at most the optimal value. The relaxed rows raise
+the optimal value by at most eta times the sum of an optimal dual vertex, whose m+n entries are at
+most D each, plus eta for the row of c. eta is chosen so that this is at most 1/(8*D^2), which
+BinarySearch can tolerate.
+*/
+func isFeasible(ctx context.Context, A [][]float64, b []float64, c []float64, lambda float64, K float64) (bool, error) {
+	n := len(c)
+	D := vertexBound(n, K)
+	eta := relaxation(len(A), n, D)
+	epsilon := eta / (math.Sqrt(float64(n)) * K)
+
+	rows := slices.Concat(A, [][]float64{util.Neg(c)}, util.NegMatrix(util.IdentityMatrix(n)))
+	rhs := slices.Concat(b, []float64{-lambda}, make([]float64, n))
+	for i := range rhs {
+		rhs[i] += eta
+	}
+
+	_, err := lp.EllipsoidContext(ctx, lp.SystemOracle{A: rows, B: rhs}, make([]float64, n), math.Sqrt(float64(n))*D+epsilon, epsilon)
+	if errors.Is(err, lp.ErrInfeasible) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// The relaxation eta of isFeasible for m rows and n variables.
+func relaxation(m int, n int, D float64) float64 {
+	return 1 / (8 * D * D * (float64(m+n)*D + 1))
+}
+
+// The bound (sqrt(n)*K)^n on the absolute values of the coordinates of vertices and their denominators.
+func vertexBound(n int, K float64) float64 {
+	return math.Pow(math.Sqrt(float64(n))*K, float64(n))
+}
+
+// The largest absolute value of all entries, at least one.
+func maxEntry(A [][]float64, b []float64, c []float64) float64 {
+	k := 1.0
+	for _, v := range slices.Concat(slices.Concat(A...), b, c) {
+		k = max(k, math.Ceil(math.Abs(v)))
+	}
+	return k
+}
@@ -1,9 +1,13 @@
 package search_test
 
 import (
+	"context"
+	"math/rand"
 	"testing"
 
+	"github.com/JonasBernard/min-cost-max-flow/lp"
 	"github.com/JonasBernard/min-cost-max-flow/search"
+	"github.com/JonasBernard/min-cost-max-flow/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +20,78 @@ func TestBinarySearch(t *testing.T) {
 	c := []float64{1, 1}
 	K := 5
 
-	result := search.BinarySearch(A, b, c, K)
-	assert.Equal(t, 1, result)
+	// the optimum is the vertex (6/5, 7/5)
+	p, q, err := search.BinarySearch(A, b, c, K)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, p)
+	assert.Equal(t, 5, q)
+
+	_, _, err = search.BinarySearch([][]float64{{1, 1}, {-1, -1}}, []float64{1, -2}, c, 2)
+	assert.ErrorIs(t, err, lp.ErrInfeasible)
+
+	_, _, err = search.BinarySearch([][]float64{{1, -1}}, []float64{1}, c, 1)
+	assert.ErrorIs(t, err, lp.ErrUnbounded)
+}
+
+func TestBinarySearchAgreesWithMaximize(t *testing.T) {
+	random := rand.New(rand.NewSource(45))
+	for range 20 {
+		m, n := 3+random.Intn(2), 3+random.Intn(2)
+		A := make([][]float64, m)
+		b := make([]float64, m)
+		for i := range m {
+			A[i] = make([]float64, n)
+			for j := range n {
+				A[i][j] = float64(random.Intn(4))
+			}
+			b[i] = float64(1 + random.Intn(3))
+		}
+		c := make([]float64, n)
+		for j := range n {
+			c[j] = float64(random.Intn(4))
+		}
+
+		_, expected, expectedErr := lp.Maximize(c, A, b)
+		p, q, err := search.BinarySearch(A, b, c, 3)
+		assert.Equal(t, expectedErr, err)
+		if expectedErr == nil {
+			assert.InDelta(t, expected, float64(p)/float64(q), 1e-9)
+		}
+	}
+}
+
+func TestBinarySearchTooLarge(t *testing.T) {
+	// 6 variables with entries of up to 10 allow denominators of about 2.2e8, whose squares float64 cannot resolve
+	A := make([][]float64, 6)
+	b := make([]float64, 6)
+	c := make([]float64, 6)
+	for i := range A {
+		A[i] = make([]float64, 6)
+		for j := range A[i] {
+			A[i][j] = float64(1 + (i+j)%10)
+		}
+		b[i] = 10
+		c[i] = 1
+	}
+	_, _, err := search.BinarySearch(A, b, c, 10)
+	assert.ErrorIs(t, err, search.ErrTooLarge)
+}
+
+func TestBinarySearchContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := search.BinarySearchContext(ctx, [][]float64{{1, 2}, {3, 1}}, []float64{4, 5}, []float64{1, 1}, 5)
+	assert.ErrorIs(t, err, util.ErrInterrupted)
+}
+
+func TestIsFeasible(t *testing.T) {
+	A := [][]float64{
+		{1, 2},
+		{3, 1},
+	}
+	b := []float64{4, 5}
+	c := []float64{1, 1}
+	assert.True(t, search.IsFeasible(A, b, c, 2.5))
+	assert.True(t, search.IsFeasible(A, b, c, 2.6))
+	assert.False(t, search.IsFeasible(A, b, c, 2.7))
 }
@@ -0,0 +1,62 @@
+/*
+Package trace lets callers follow what the solvers in this module are doing while they run.
+
+Solvers report Events to an Observer that is handed to them as an option. Each solver package
+defines its own event types (per simplex iteration, per flow augmentation, ...) and, where it used
+to print tables, a table observer that renders them like before. SlogObserver forwards any event
+to a log/slog logger.
+*/
+package trace
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Anything a solver reports while it runs.
+type Event interface {
+	// A short constant message that names the kind of event, e.g. "simplex iteration".
+	Kind() string
+	// The content of the event as structured logging attributes.
+	Attrs() []slog.Attr
+}
+
+type Observer interface {
+	Observe(Event)
+}
+
+// Adapter to use an ordinary function as Observer.
+type ObserverFunc func(Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Forwards every event to all observers in the slice, in order.
+type Observers []Observer
+
+func (os Observers) Observe(e Event) {
+	for _, o := range os {
+		if o != nil {
+			o.Observe(e)
+		}
+	}
+}
+
+// Writes every event as one log record using the event kind as message.
+type SlogObserver struct {
+	Logger *slog.Logger
+	Level  slog.Level
+}
+
+func NewSlogObserver(logger *slog.Logger, level slog.Level) SlogObserver {
+	return SlogObserver{Logger: logger, Level: level}
+}
+
+func (o SlogObserver) Observe(e Event) {
+	logger := o.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(context.Background(), o.Level, e.Kind(), e.Attrs()...)
+}
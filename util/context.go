@@ -0,0 +1,26 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/*
+Reported by the iterative solvers when they stop early because their context was cancelled
+or its deadline passed. Whatever a solver returns alongside this error is the best incumbent
+it knew about at that point.
+*/
+var ErrInterrupted = errors.New("solver interrupted")
+
+/*
+Returns nil as long as ctx is still live. Otherwise it returns an error that matches both
+ErrInterrupted and the context's own error (context.Canceled or context.DeadlineExceeded)
+under errors.Is.
+*/
+func Interrupted(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInterrupted, err)
+	}
+	return nil
+}
@@ -5,15 +5,15 @@ import (
 	"slices"
 )
 
-func Transpose(A [][]float64) [][]float64 {
+func Transpose[T any](A [][]T) [][]T {
 	if len(A) == 0 {
-		return [][]float64{}
+		return [][]T{}
 	}
 	M := len(A)
 	N := len(A[0])
-	AT := make([][]float64, N)
+	AT := make([][]T, N)
 	for i := range N {
-		AT[i] = make([]float64, M)
+		AT[i] = make([]T, M)
 		for j := range M {
 			AT[i][j] = A[j][i]
 		}
@@ -21,28 +21,28 @@ func Transpose(A [][]float64) [][]float64 {
 	return AT
 }
 
-func GetRows(A [][]float64, rowIndices []int) [][]float64 {
-	rows := make([][]float64, len(rowIndices))
+func GetRows[T any](A [][]T, rowIndices []int) [][]T {
+	rows := make([][]T, len(rowIndices))
 	for i, rowIndex := range rowIndices {
 		rows[i] = A[rowIndex]
 	}
 	return rows
 }
 
-func GetValues(a []float64, indices []int) []float64 {
-	rows := make([]float64, len(indices))
+func GetValues[T any](a []T, indices []int) []T {
+	rows := make([]T, len(indices))
 	for i, rowIndex := range indices {
 		rows[i] = a[rowIndex]
 	}
 	return rows
 }
 
-func GetRow(A [][]float64, rowIndex int) []float64 {
+func GetRow[T any](A [][]T, rowIndex int) []T {
 	return A[rowIndex]
 }
 
-func GetColumn(A [][]float64, rowIndex int) []float64 {
-	col := make([]float64, len(A))
+func GetColumn[T any](A [][]T, rowIndex int) []T {
+	col := make([]T, len(A))
 	for i := range A {
 		col[i] = A[i][rowIndex]
 	}
@@ -184,7 +184,7 @@ func IdentityMatrix(n int) [][]float64 {
 	return I
 }
 
-func ConcatColumns(A [][]float64, B [][]float64) [][]float64 {
+func ConcatColumns[T any](A [][]T, B [][]T) [][]T {
 	if len(A) == 0 {
 		return B
 	}
@@ -195,7 +195,7 @@ func ConcatColumns(A [][]float64, B [][]float64) [][]float64 {
 		panic("Matrices must have the same number of rows to concatenate")
 	}
 	M := len(A)
-	C := make([][]float64, M)
+	C := make([][]T, M)
 	for i := range M {
 		C[i] = slices.Concat(A[i], B[i])
 	}
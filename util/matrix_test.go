@@ -1,6 +1,7 @@
 package util_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/JonasBernard/min-cost-max-flow/util"
@@ -29,3 +30,43 @@ func TestGetRowsThenTranspose(t *testing.T) {
 		}
 	}
 }
+
+func TestTolerance(t *testing.T) {
+	tol := util.Tolerance{Absolute: 1e-9, Relative: 1e-6}
+	// variables, because constant expressions are evaluated exactly by the compiler
+	a, b := 0.1, 0.2
+
+	if !tol.Equal(a+b, 0.3) {
+		t.Errorf("expected 0.1+0.2 and 0.3 to be equal")
+	}
+	if !tol.Equal(1e9, 1e9+1) {
+		t.Errorf("expected 1e9 and 1e9+1 to be equal relative to their size")
+	}
+	if tol.Equal(1, 1.001) {
+		t.Errorf("expected 1 and 1.001 to differ")
+	}
+	if tol.Sign(-1e-12) != 0 || tol.Sign(-1e-3) != -1 || tol.Sign(1e-3) != 1 {
+		t.Errorf("unexpected signs")
+	}
+	if tol.Less(0.3, a+b) || tol.Greater(a+b, 0.3) {
+		t.Errorf("expected 0.1+0.2 to be neither smaller nor greater than 0.3")
+	}
+	if (util.Tolerance{}).Equal(a+b, 0.3) {
+		t.Errorf("expected the zero tolerance to compare exactly")
+	}
+}
+
+func TestNumber(t *testing.T) {
+	if !util.IsIntegral[int64]() || util.IsIntegral[float64]() {
+		t.Errorf("expected only int64 to be integral")
+	}
+	if util.Infinity[int64]() != math.MaxInt64 || !math.IsInf(util.Infinity[float64](), 1) {
+		t.Errorf("unexpected infinities")
+	}
+	if util.EqualWithin[int64](util.DefaultTolerance, 1<<40, 1<<40+1) {
+		t.Errorf("expected integers to be compared exactly")
+	}
+	if !util.EqualWithin(util.DefaultTolerance, 1e12, 1e12+1e-6) {
+		t.Errorf("expected floats to be compared within the tolerance")
+	}
+}
@@ -0,0 +1,52 @@
+package util
+
+import "math"
+
+/*
+The numeric types graphs, networks and flows can be built on.
+Instances with int64 weights and capacities are solved in exact integer arithmetic.
+*/
+type Number interface {
+	~int64 | ~float64
+}
+
+// Whether N is an integer type.
+func IsIntegral[N Number]() bool {
+	return N(1)/N(2) == 0
+}
+
+// The largest value of N, that is +Inf for floating point types and math.MaxInt64 for integer types.
+func Infinity[N Number]() N {
+	if IsIntegral[N]() {
+		var max int64 = math.MaxInt64
+		return N(max)
+	}
+	return N(math.Inf(1))
+}
+
+// Same as Tolerance.Equal for floating point types, but exact for integer types.
+func EqualWithin[N Number](tol Tolerance, a N, b N) bool {
+	if IsIntegral[N]() {
+		return a == b
+	}
+	return tol.Equal(float64(a), float64(b))
+}
+
+// Same as Tolerance.Compare for floating point types, but exact for integer types.
+func CompareWithin[N Number](tol Tolerance, a N, b N) int {
+	if IsIntegral[N]() {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return tol.Compare(float64(a), float64(b))
+}
+
+// Same as Tolerance.Sign for floating point types, but exact for integer types.
+func SignWithin[N Number](tol Tolerance, a N) int {
+	return CompareWithin(tol, a, 0)
+}
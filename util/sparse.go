@@ -0,0 +1,263 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+)
+
+/*
+Sparse matrix in compressed sparse row format. The non-zero entries of row i are
+Values[RowPtr[i]:RowPtr[i+1]] in the columns ColIndices[RowPtr[i]:RowPtr[i+1]], sorted by column.
+*/
+type CSR struct {
+	Rows       int
+	Cols       int
+	RowPtr     []int
+	ColIndices []int
+	Values     []float64
+}
+
+/*
+Sparse matrix in compressed sparse column format. The non-zero entries of column j are
+Values[ColPtr[j]:ColPtr[j+1]] in the rows RowIndices[ColPtr[j]:ColPtr[j+1]], sorted by row.
+*/
+type CSC struct {
+	Rows       int
+	Cols       int
+	ColPtr     []int
+	RowIndices []int
+	Values     []float64
+}
+
+// Compresses a dense matrix with cols columns, leaving out all zero entries.
+func NewCSR(A [][]float64, cols int) *CSR {
+	S := &CSR{Rows: len(A), Cols: cols, RowPtr: make([]int, 1, len(A)+1)}
+	for _, row := range A {
+		if len(row) != cols {
+			panic(fmt.Sprintf("all rows must have %d columns, got %d", cols, len(row)))
+		}
+		for j, v := range row {
+			if v != 0 {
+				S.ColIndices = append(S.ColIndices, j)
+				S.Values = append(S.Values, v)
+			}
+		}
+		S.RowPtr = append(S.RowPtr, len(S.Values))
+	}
+	return S
+}
+
+/*
+Builds a rows x cols matrix from a list of entries (rowIndices[k], colIndices[k], values[k]).
+Entries with the same position are summed up, as is common when assembling constraint matrices.
+*/
+func NewCSRFromTriplets(rows int, cols int, rowIndices []int, colIndices []int, values []float64) *CSR {
+	if len(rowIndices) != len(colIndices) || len(rowIndices) != len(values) {
+		panic("triplet slices must be of the same length")
+	}
+	entries := make([]map[int]float64, rows)
+	for k := range values {
+		i, j := rowIndices[k], colIndices[k]
+		if i < 0 || i >= rows || j < 0 || j >= cols {
+			panic(fmt.Sprintf("entry (%d, %d) is out of bounds for a %dx%d matrix", i, j, rows, cols))
+		}
+		if entries[i] == nil {
+			entries[i] = make(map[int]float64)
+		}
+		entries[i][j] += values[k]
+	}
+	S := &CSR{Rows: rows, Cols: cols, RowPtr: make([]int, 1, rows+1)}
+	for _, row := range entries {
+		columns := make([]int, 0, len(row))
+		for j, v := range row {
+			if v != 0 {
+				columns = append(columns, j)
+			}
+		}
+		slices.Sort(columns)
+		for _, j := range columns {
+			S.ColIndices = append(S.ColIndices, j)
+			S.Values = append(S.Values, row[j])
+		}
+		S.RowPtr = append(S.RowPtr, len(S.Values))
+	}
+	return S
+}
+
+// The number of non-zero entries.
+func (A *CSR) NonZeros() int {
+	return len(A.Values)
+}
+
+// The column indices and values of the non-zero entries of row i. The slices must not be modified.
+func (A *CSR) Row(i int) (columns []int, values []float64) {
+	return A.ColIndices[A.RowPtr[i]:A.RowPtr[i+1]], A.Values[A.RowPtr[i]:A.RowPtr[i+1]]
+}
+
+// Row i as dense vector.
+func (A *CSR) DenseRow(i int) []float64 {
+	row := make([]float64, A.Cols)
+	columns, values := A.Row(i)
+	for k, j := range columns {
+		row[j] = values[k]
+	}
+	return row
+}
+
+// The dot product of row i and v.
+func (A *CSR) RowDot(i int, v []float64) float64 {
+	sum := 0.0
+	columns, values := A.Row(i)
+	for k, j := range columns {
+		sum += values[k] * v[j]
+	}
+	return sum
+}
+
+// Computes A@v.
+func (A *CSR) MulVec(v []float64) []float64 {
+	if len(v) != A.Cols {
+		panic("Matrix columns must match vector length: got " + fmt.Sprint(A.Cols) + " and " + fmt.Sprint(len(v)))
+	}
+	result := make([]float64, A.Rows)
+	for i := range A.Rows {
+		result[i] = A.RowDot(i, v)
+	}
+	return result
+}
+
+// The submatrix of the given rows, in the given order.
+func (A *CSR) GetRows(rowIndices []int) *CSR {
+	S := &CSR{Rows: len(rowIndices), Cols: A.Cols, RowPtr: make([]int, 1, len(rowIndices)+1)}
+	for _, i := range rowIndices {
+		columns, values := A.Row(i)
+		S.ColIndices = append(S.ColIndices, columns...)
+		S.Values = append(S.Values, values...)
+		S.RowPtr = append(S.RowPtr, len(S.Values))
+	}
+	return S
+}
+
+// Stacks the rows of B below the rows of A.
+func (A *CSR) ConcatRows(B *CSR) *CSR {
+	if A.Cols != B.Cols {
+		panic("Matrices must have the same number of columns: got " + fmt.Sprint(A.Cols) + " and " + fmt.Sprint(B.Cols))
+	}
+	S := &CSR{
+		Rows:       A.Rows + B.Rows,
+		Cols:       A.Cols,
+		RowPtr:     slices.Clone(A.RowPtr),
+		ColIndices: slices.Concat(A.ColIndices, B.ColIndices),
+		Values:     slices.Concat(A.Values, B.Values),
+	}
+	for _, p := range B.RowPtr[1:] {
+		S.RowPtr = append(S.RowPtr, p+len(A.Values))
+	}
+	return S
+}
+
+// The same matrix in compressed sparse column format.
+func (A *CSR) ToCSC() *CSC {
+	T := A.Transpose()
+	return &CSC{Rows: A.Rows, Cols: A.Cols, ColPtr: T.RowPtr, RowIndices: T.ColIndices, Values: T.Values}
+}
+
+// Computes A^T in O(nonzeros + rows + cols).
+func (A *CSR) Transpose() *CSR {
+	counts := make([]int, A.Cols+1)
+	for _, j := range A.ColIndices {
+		counts[j+1]++
+	}
+	for j := range A.Cols {
+		counts[j+1] += counts[j]
+	}
+	T := &CSR{
+		Rows:       A.Cols,
+		Cols:       A.Rows,
+		RowPtr:     slices.Clone(counts),
+		ColIndices: make([]int, len(A.Values)),
+		Values:     make([]float64, len(A.Values)),
+	}
+	next := counts[:A.Cols]
+	// rows are visited in increasing order, so every row of T ends up sorted
+	for i := range A.Rows {
+		columns, values := A.Row(i)
+		for k, j := range columns {
+			T.ColIndices[next[j]] = i
+			T.Values[next[j]] = values[k]
+			next[j]++
+		}
+	}
+	return T
+}
+
+func (A *CSR) Dense() [][]float64 {
+	D := make([][]float64, A.Rows)
+	for i := range D {
+		D[i] = A.DenseRow(i)
+	}
+	return D
+}
+
+// Compresses a dense matrix with cols columns, leaving out all zero entries.
+func NewCSC(A [][]float64, cols int) *CSC {
+	return NewCSR(A, cols).ToCSC()
+}
+
+// The row indices and values of the non-zero entries of column j. The slices must not be modified.
+func (A *CSC) Column(j int) (rows []int, values []float64) {
+	return A.RowIndices[A.ColPtr[j]:A.ColPtr[j+1]], A.Values[A.ColPtr[j]:A.ColPtr[j+1]]
+}
+
+// Column j as dense vector.
+func (A *CSC) DenseColumn(j int) []float64 {
+	column := make([]float64, A.Rows)
+	rows, values := A.Column(j)
+	for k, i := range rows {
+		column[i] = values[k]
+	}
+	return column
+}
+
+// Computes A@v.
+func (A *CSC) MulVec(v []float64) []float64 {
+	if len(v) != A.Cols {
+		panic("Matrix columns must match vector length: got " + fmt.Sprint(A.Cols) + " and " + fmt.Sprint(len(v)))
+	}
+	result := make([]float64, A.Rows)
+	for j := range A.Cols {
+		if v[j] == 0 {
+			continue
+		}
+		rows, values := A.Column(j)
+		for k, i := range rows {
+			result[i] += values[k] * v[j]
+		}
+	}
+	return result
+}
+
+// Computes A^T@v, that is the dot product of every column with v.
+func (A *CSC) MulVecTransposed(v []float64) []float64 {
+	if len(v) != A.Rows {
+		panic("Matrix rows must match vector length: got " + fmt.Sprint(A.Rows) + " and " + fmt.Sprint(len(v)))
+	}
+	result := make([]float64, A.Cols)
+	for j := range A.Cols {
+		rows, values := A.Column(j)
+		for k, i := range rows {
+			result[j] += values[k] * v[i]
+		}
+	}
+	return result
+}
+
+// The same matrix in compressed sparse row format.
+func (A *CSC) ToCSR() *CSR {
+	// the arrays of A in CSC are those of A^T in CSR
+	return (&CSR{Rows: A.Cols, Cols: A.Rows, RowPtr: A.ColPtr, ColIndices: A.RowIndices, Values: A.Values}).Transpose()
+}
+
+func (A *CSC) Dense() [][]float64 {
+	return A.ToCSR().Dense()
+}
@@ -0,0 +1,38 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/JonasBernard/min-cost-max-flow/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSR(t *testing.T) {
+	A := [][]float64{
+		{1, 0, 2},
+		{0, 0, 0},
+		{0, 3, 0},
+		{4, 0, 5},
+	}
+	S := util.NewCSR(A, 3)
+
+	assert.Equal(t, 5, S.NonZeros())
+	assert.Equal(t, A, S.Dense())
+	assert.Equal(t, util.Transpose(A), S.Transpose().Dense())
+	assert.Equal(t, A, S.ToCSC().Dense())
+	assert.Equal(t, util.MatMul(A, []float64{1, 2, 3}), S.MulVec([]float64{1, 2, 3}))
+	assert.Equal(t, util.MatMul(A, []float64{1, 2, 3}), S.ToCSC().MulVec([]float64{1, 2, 3}))
+	assert.Equal(t, util.MatMul(util.Transpose(A), []float64{1, 2, 3, 4}), S.ToCSC().MulVecTransposed([]float64{1, 2, 3, 4}))
+	assert.Equal(t, util.GetRows(A, []int{3, 0}), S.GetRows([]int{3, 0}).Dense())
+	assert.Equal(t, append(util.GetRows(A, []int{0, 2}), A...), S.GetRows([]int{0, 2}).ConcatRows(S).Dense())
+	assert.Equal(t, util.GetColumn(A, 2), S.ToCSC().DenseColumn(2))
+}
+
+func TestCSRFromTriplets(t *testing.T) {
+	S := util.NewCSRFromTriplets(2, 3, []int{1, 0, 1, 1}, []int{2, 1, 0, 2}, []float64{1, 2, 3, 4})
+
+	assert.Equal(t, [][]float64{{0, 2, 0}, {3, 0, 5}}, S.Dense())
+	columns, values := S.Row(1)
+	assert.Equal(t, []int{0, 2}, columns)
+	assert.Equal(t, []float64{3, 5}, values)
+}
@@ -0,0 +1,63 @@
+package util
+
+import "math"
+
+/*
+Decides when two float64 values are considered equal. Two values are equal if they differ by at
+most Absolute or by at most Relative times the larger of their absolute values.
+A value is zero if its absolute value is at most Absolute.
+
+The zero Tolerance compares exactly.
+*/
+type Tolerance struct {
+	Absolute float64
+	Relative float64
+}
+
+// The tolerance used by lp, graph, network and matching unless configured otherwise.
+var DefaultTolerance = Tolerance{Absolute: 1e-9, Relative: 1e-9}
+
+func (t Tolerance) Equal(a float64, b float64) bool {
+	if a == b {
+		return true
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	diff := math.Abs(a - b)
+	return diff <= t.Absolute || diff <= t.Relative*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func (t Tolerance) IsZero(a float64) bool {
+	return math.Abs(a) <= t.Absolute
+}
+
+// Returns -1, 0 or +1 depending on whether a is negative, zero or positive up to the tolerance.
+func (t Tolerance) Sign(a float64) int {
+	switch {
+	case t.IsZero(a):
+		return 0
+	case a < 0:
+		return -1
+	}
+	return 1
+}
+
+// Returns -1, 0 or +1 depending on whether a is smaller than, equal to or greater than b up to the tolerance.
+func (t Tolerance) Compare(a float64, b float64) int {
+	switch {
+	case t.Equal(a, b):
+		return 0
+	case a < b:
+		return -1
+	}
+	return 1
+}
+
+func (t Tolerance) Less(a float64, b float64) bool {
+	return t.Compare(a, b) < 0
+}
+
+func (t Tolerance) Greater(a float64, b float64) bool {
+	return t.Compare(a, b) > 0
+}